@@ -16,22 +16,52 @@
 package client
 
 import (
+	"crypto/tls"
 	"strings"
 	"terraform-provider-solacebroker/cmd/generator"
 	"terraform-provider-solacebroker/internal/broker"
 	"terraform-provider-solacebroker/internal/semp"
 )
 
-func CliClient(cliParams generator.CliParams) *semp.Client {
+// CliClient creates the SEMP client of the generator from the connection params
+func CliClient(cliParams generator.CliParams) (*semp.Client, error) {
+	clientCertificate, err := loadClientCertificate(cliParams)
+	if err != nil {
+		return nil, err
+	}
+	caCertificates, err := semp.ReadPEM("ca_certificate", *cliParams.Ca_certificate, "ca_bundle_file", *cliParams.Ca_bundle_file)
+	if err != nil {
+		return nil, err
+	}
+	rootCAs, err := semp.LoadCertPool(caCertificates)
+	if err != nil {
+		return nil, err
+	}
 	client := semp.NewClient(
 		getFullSempAPIURL(*cliParams.Url),
 		*cliParams.Insecure_skip_verify,
 		false, // this is a client for the generator
 		semp.BasicAuth(*cliParams.Username, *cliParams.Password),
 		semp.BearerToken(*cliParams.Bearer_token),
+		semp.OAuthClientCredentials(*cliParams.Oauth_token_url, *cliParams.Oauth_client_id, *cliParams.Oauth_client_secret, strings.Fields(*cliParams.Oauth_scopes)),
+		semp.ClientCertificate(clientCertificate),
+		semp.RootCAs(rootCAs),
+		semp.TLSServerName(*cliParams.Tls_server_name),
 		semp.Retries(*cliParams.Retries, *cliParams.Retry_min_interval, *cliParams.Retry_max_interval),
 		semp.RequestLimits(*cliParams.Request_timeout_duration, *cliParams.Request_min_interval))
-	return client
+	return client, nil
+}
+
+func loadClientCertificate(cliParams generator.CliParams) (*tls.Certificate, error) {
+	certificate, err := semp.ReadPEM("client_certificate", *cliParams.Client_certificate, "client_certificate_file", *cliParams.Client_certificate_file)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := semp.ReadPEM("client_private_key", *cliParams.Client_private_key, "client_private_key_file", *cliParams.Client_private_key_file)
+	if err != nil {
+		return nil, err
+	}
+	return semp.LoadClientCertificate(certificate, privateKey)
 }
 
 func getFullSempAPIURL(url string) string {
@@ -0,0 +1,204 @@
+// Package cmd terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"terraform-provider-solacebroker/cmd/generator"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// sourceConnectionFlags are the connection flags that can also be set for one source of the diff command only, with
+// the from_ or to_ prefix, to compare brokers with different credentials or certificates
+var sourceConnectionFlags = []string{
+	"username", "password", "bearer_token",
+	"oauth_token_url", "oauth_client_id", "oauth_client_secret", "oauth_scopes",
+	"client_certificate", "client_certificate_file", "client_private_key", "client_private_key_file",
+	"insecure_skip_verify", "ca_certificate", "ca_bundle_file", "tls_server_name",
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff <resource type> <provider-specific identifier> <from source> <to source>",
+	Short: "Compares the configuration of a specified PubSub+ event broker object and all child objects between two brokers or snapshots",
+	Long: `The diff command on the provider binary compares the configuration of the specified object and all child objects known to the provider between two sources, and reports added, removed and changed objects and attributes.
+This is not a Terraform command.
+
+  <binary> diff [flags] <resource type> <provider-specific identifier> <from source> <to source>
+
+  where:
+		<binary> is the broker provider binary
+		[flags] are the supported options, which mirror the configuration options for the provider object (for example --retry_wait_max=90s) and can also be set via environment variables in the same way. The connection options apply to both brokers, the credential and TLS options can be overridden for one of them with the from_ or to_ prefix (for example --to_username or SOLACEBROKER_TO_USERNAME).
+		<resource type> the resource type of the specified object, for example solacebroker_msg_vpn
+		<provider-specific identifier> the import identifier of the specified object instance on the brokers, refer to the resource type of the object in the provider documentation
+		<from source>, <to source> are either a broker base URL starting with http:// or https://, or a snapshot file taken by the snapshot command, which contains its own identifier
+
+Example:
+  SOLACEBROKER_USERNAME=adminuser SOLACEBROKER_PASSWORD=pass \
+	terraform-provider-solacebroker diff solacebroker_msg_vpn test https://staging.example.org:1943 prod-snapshot.json.gz
+
+This command will report the differences of the 'test' message VPN and any child objects on the staging broker compared to the snapshot.
+
+  SOLACEBROKER_FROM_USERNAME=adminuser SOLACEBROKER_FROM_PASSWORD=pass SOLACEBROKER_TO_BEARER_TOKEN=token \
+	terraform-provider-solacebroker diff solacebroker_msg_vpn test https://staging.example.org:1943 https://prod.example.org:1943
+
+This command will compare the 'test' message VPN on the staging broker, using basic authentication, with the production broker, using a bearer token.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 4 {
+			// Print the help message if the required arguments are not provided
+			_ = cmd.Help()
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		outputFormat, _ := flags.GetString("output_format")
+		if outputFormat != "text" && outputFormat != "json" {
+			generator.ExitWithError(fmt.Sprintf("\nError: Unsupported output format %s, must be text or json\n\n", outputFormat))
+		}
+		// The diff is the result on stdout
+		generator.LogWriter = os.Stderr
+
+		brokerObjectType := generator.BrokerObjectType(strings.TrimPrefix(flags.Arg(0), "solacebroker_"))
+		providerSpecificIdentifier := flags.Arg(1)
+		if !generator.IsBrokerObjectType(brokerObjectType) {
+			generator.ExitWithError("\nError: Broker resource not found by terraform name : " + string(brokerObjectType) + "\n\n")
+		}
+
+		from := diffSource(cmd, "from_", brokerObjectType, providerSpecificIdentifier, flags.Arg(2))
+		to := diffSource(cmd, "to_", brokerObjectType, providerSpecificIdentifier, flags.Arg(3))
+		diff, err := generator.DiffBrokerConfigs(from, to)
+		if err != nil {
+			generator.ExitWithError("Failed to compare broker config, " + err.Error())
+		}
+		generator.LogCLIInfo("Compared all resources.\n")
+		if err := generator.WriteDiff(os.Stdout, diff, outputFormat); err != nil {
+			generator.ExitWithError("Failed to write diff, " + err.Error())
+		}
+
+		if detailedExitCode, _ := flags.GetBool("detailed_exitcode"); detailedExitCode && len(diff.Objects) > 0 {
+			os.Exit(2)
+		}
+		os.Exit(0)
+	},
+}
+
+// diffSource fetches a source from the broker if it is a URL, otherwise reads it from a snapshot file. The connection
+// flags with the prefix of the source override the shared ones.
+func diffSource(cmd *cobra.Command, prefix string, brokerObjectType generator.BrokerObjectType, providerSpecificIdentifier string, source string) *generator.DiffSource {
+	cliParams, err := sourceCliParams(cmd.Flags(), prefix)
+	if err != nil {
+		generator.ExitWithError("\nError: " + err.Error() + "\n\n")
+	}
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		cliParams, err = generator.UpdateOfflineCliParamsWithEnv(cliParams)
+		if err != nil {
+			generator.ExitWithError("\nError: " + err.Error() + "\n\n")
+		}
+		snapshot, err := generator.ReadSnapshot(source)
+		if err != nil {
+			generator.ExitWithError("Failed to read snapshot, " + err.Error())
+		}
+		if snapshot.ObjectType != string(brokerObjectType) {
+			generator.ExitWithError(fmt.Sprintf("\nError: The snapshot %s contains a %s object, not %s\n\n", source, "solacebroker_"+snapshot.ObjectType, "solacebroker_"+brokerObjectType))
+		}
+		generator.LogCLIInfo(fmt.Sprintf("Reading config for object and its child-objects from snapshot %s, identifier: %s\n", source, snapshot.Identifier))
+		diffSource, err := generator.DiffSourceFromSnapshot(cliParams, snapshot, source)
+		if err != nil {
+			generator.ExitWithError("Failed to read broker config from snapshot, " + err.Error())
+		}
+		return diffSource
+	}
+	cliParams.Url = &source
+	cliParams, err = generator.UpdateCliParamsWithEnv(cliParams)
+	if err != nil {
+		generator.ExitWithError("\nError: " + err.Error() + "\n\n")
+	}
+	cliClient, _ := connectToBroker(cmd, cliParams)
+	generator.LogCLIInfo(fmt.Sprintf("Fetching config for object and its child-objects from broker %s, identifier: %s\n", source, providerSpecificIdentifier))
+	diffSource, err := generator.DiffSourceFromBroker(cliParams, cmd.Context(), cliClient, brokerObjectType, providerSpecificIdentifier, source)
+	if err != nil {
+		generator.ExitWithError("Failed to fetch broker config, " + err.Error())
+	}
+	return diffSource
+}
+
+// sourceCliParams returns the params from the flags, with the connection params overridden by the flags of a source,
+// or their environment variables
+func sourceCliParams(flags *pflag.FlagSet, prefix string) (generator.CliParams, error) {
+	cliParams := cliParamsFromFlags(flags)
+	stringParams := map[string]**string{
+		"username":                &cliParams.Username,
+		"password":                &cliParams.Password,
+		"bearer_token":            &cliParams.Bearer_token,
+		"oauth_token_url":         &cliParams.Oauth_token_url,
+		"oauth_client_id":         &cliParams.Oauth_client_id,
+		"oauth_client_secret":     &cliParams.Oauth_client_secret,
+		"oauth_scopes":            &cliParams.Oauth_scopes,
+		"client_certificate":      &cliParams.Client_certificate,
+		"client_certificate_file": &cliParams.Client_certificate_file,
+		"client_private_key":      &cliParams.Client_private_key,
+		"client_private_key_file": &cliParams.Client_private_key_file,
+		"ca_certificate":          &cliParams.Ca_certificate,
+		"ca_bundle_file":          &cliParams.Ca_bundle_file,
+		"tls_server_name":         &cliParams.Tls_server_name,
+	}
+	for name, param := range stringParams {
+		if flags.Changed(prefix + name) {
+			value, _ := flags.GetString(prefix + name)
+			*param = &value
+		} else if os.Getenv("SOLACEBROKER_"+strings.ToUpper(prefix+name)) != "" {
+			*param, _ = generator.StringParamWithEnv(prefix+name, nil, false, "")
+		}
+	}
+	if flags.Changed(prefix + "insecure_skip_verify") {
+		insecureSkipVerify, _ := flags.GetBool(prefix + "insecure_skip_verify")
+		cliParams.Insecure_skip_verify = &insecureSkipVerify
+	} else if os.Getenv("SOLACEBROKER_"+strings.ToUpper(prefix)+"INSECURE_SKIP_VERIFY") != "" {
+		insecureSkipVerify, err := generator.BooleanParamWithEnv(prefix+"insecure_skip_verify", nil, false, false)
+		if err != nil {
+			return cliParams, err
+		}
+		cliParams.Insecure_skip_verify = insecureSkipVerify
+	}
+	return cliParams, nil
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	addConnectionFlags(diffCmd)
+	for _, source := range []string{"from", "to"} {
+		for _, name := range sourceConnectionFlags {
+			flag := diffCmd.PersistentFlags().Lookup(name)
+			usage := fmt.Sprintf("%s for the %s source, overrides --%s", flag.Usage, source, name)
+			if flag.Value.Type() == "bool" {
+				diffCmd.PersistentFlags().Bool(source+"_"+name, false, usage)
+			} else {
+				diffCmd.PersistentFlags().String(source+"_"+name, "", usage)
+			}
+		}
+	}
+	diffCmd.PersistentFlags().StringArray("include_type", nil, "Only compare child objects of resource types matching the pattern, repeatable")
+	diffCmd.PersistentFlags().StringArray("exclude_type", nil, "Skip child objects of resource types matching the pattern and their children, repeatable")
+	diffCmd.PersistentFlags().StringArray("include_name", nil, "Only compare child objects with names matching the [<type pattern>=]<name pattern>, repeatable")
+	diffCmd.PersistentFlags().StringArray("exclude_name", nil, "Skip child objects with names matching the [<type pattern>=]<name pattern> and their children, repeatable")
+	diffCmd.PersistentFlags().Int64("parallelism", 1, "Maximum number of concurrent SEMP requests to fetch child objects")
+	diffCmd.PersistentFlags().String("output_format", "text", "Format of the reported differences, text or json")
+	diffCmd.PersistentFlags().Bool("detailed_exitcode", false, "Exit with status 2 if there are differences, 0 if there are none and 1 on errors")
+}
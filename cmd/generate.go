@@ -24,8 +24,10 @@ import (
 	"terraform-provider-solacebroker/cmd/generator"
 	"terraform-provider-solacebroker/internal/broker/generated"
 	"terraform-provider-solacebroker/internal/semp"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // generateCmd represents the generate command
@@ -42,7 +44,13 @@ This is not a Terraform command. You can download the provider binary and execut
 		[flags] are the supported options, which mirror the configuration options for the provider object (for example --url=https://localhost:1943 and --retry_wait_max=90s) and can also be set via environment variables in the same way.
 		<terraform resource address> how to address the specified object instance in the generated configuration, in the form of <resource_type>.<resource_name>
 		<provider-specific identifier> the import identifier of the specified object instance, refer to the resource type of the object in the provider documentation
-		<filename> is the name of the generated file
+		<filename> is the name of the generated file, or of a directory if it ends with "/" or is an existing directory. A directory will contain providers.tf, variables.tf and one file per resource type.
+		With --output_format=json, the files are generated in the Terraform JSON configuration syntax and have the .tf.json extension.
+		With --module, <filename> is always a directory and will contain a reusable Terraform module.
+
+With --from_snapshot, the configuration is generated from a snapshot taken by the snapshot command instead of the broker, without broker access:
+
+  <binary> generate [flags] --from_snapshot=<snapshot file> <terraform resource address> <filename>
 
 Example:
   SOLACEBROKER_USERNAME=adminuser SOLACEBROKER_PASSWORD=pass \
@@ -52,75 +60,23 @@ This command will create a file vpn-config.tf that contains a resource definitio
 The message VPN resource address in the generated configuration will be 'solacebroker_msg_vpn.myvpn'.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
+		flags := cmd.Flags()
+		cliParams := cliParamsFromFlags(flags)
+		snapshotFileName, _ := flags.GetString("from_snapshot")
+		if snapshotFileName != "" {
+			generateFromSnapshot(cmd, cliParams, snapshotFileName)
+			os.Exit(0)
+		}
 		if len(args) < 3 {
 			// Print the help message if the required arguments are not provided
 			_ = cmd.Help()
 			os.Exit(1)
 		}
 
-		flags := cmd.Flags()
-		cliParams := generator.CliParams{}
-		if flags.Changed("url") {
-			if url, err := flags.GetString("url"); err == nil {
-				cliParams.Url = &url
-			}
-		}
-		if flags.Changed("username") {
-			if username, err := flags.GetString("username"); err == nil {
-				cliParams.Username = &username
-			}
-		}
-		if flags.Changed("password") {
-			if password, err := flags.GetString("password"); err == nil {
-				cliParams.Password = &password
-			}
-		}
-		if flags.Changed("bearer_token") {
-			if bearerToken, err := flags.GetString("bearer_token"); err == nil {
-				cliParams.Bearer_token = &bearerToken
-			}
-		}
-		if flags.Changed("retries") {
-			if retries, err := flags.GetInt64("retries"); err == nil {
-				cliParams.Retries = &retries
-			}
-		}
-		if flags.Changed("retry_min_interval") {
-			if retryMinInterval, err := flags.GetDuration("retry_min_interval"); err == nil {
-				cliParams.Request_min_interval = &retryMinInterval
-			}
-		}
-		if flags.Changed("retry_max_interval") {
-			if retryMaxInterval, err := flags.GetDuration("retry_max_interval"); err == nil {
-				cliParams.Retry_max_interval = &retryMaxInterval
-			}
-		}
-		if flags.Changed("request_timeout_duration") {
-			if requestTimeoutDuration, err := flags.GetDuration("request_timeout_duration"); err == nil {
-				cliParams.Request_timeout_duration = &requestTimeoutDuration
-			}
-		}
-		if flags.Changed("request_min_interval") {
-			if requestMinInterval, err := flags.GetDuration("request_min_interval"); err == nil {
-				cliParams.Request_min_interval = &requestMinInterval
-			}
-		}
-		if flags.Changed("insecure_skip_verify") {
-			if insecureSkipVerify, err := flags.GetBool("insecure_skip_verify"); err == nil {
-				cliParams.Insecure_skip_verify = &insecureSkipVerify
-			}
-		}
-		if flags.Changed("skip_api_check") {
-			if skipApiCheck, err := flags.GetBool("skip_api_check"); err == nil {
-				cliParams.Skip_api_check = &skipApiCheck
-			}
-		}
 		// Complement params with env as required, also ensure valid values for all
-		cliParams = generator.UpdateCliParamsWithEnv(cliParams)
-
-		cliClient := client.CliClient(cliParams)
-		if cliClient == nil {
-			generator.ExitWithError("Error creating SEMP Client")
+		cliParams, err := generator.UpdateCliParamsWithEnv(cliParams)
+		if err != nil {
+			generator.ExitWithError("\nError: " + err.Error() + "\n\n")
 		}
 
 		brokerObjectType := flags.Arg(0)
@@ -137,62 +93,307 @@ The message VPN resource address in the generated configuration will be 'solaceb
 			os.Exit(1)
 		}
 
-		fileName := flags.Arg(2)
-		if len(fileName) == 0 {
-			generator.LogCLIError("\nError: Terraform file name not specified.\n\n")
-			_ = cmd.Help()
-			os.Exit(1)
-		}
+		fileName := terraformFileName(cmd, cliParams, flags.Arg(2))
 
-		if !strings.HasSuffix(fileName, ".tf") {
-			fileName = fileName + ".tf"
-		}
+		cliClient, _ := connectToBroker(cmd, cliParams)
 
-		skipApiCheck := *cliParams.Skip_api_check
-		//Confirm SEMP version and connection via client
-		aboutPath := "/about/api"
-		result, err := cliClient.RequestWithoutBody(cmd.Context(), http.MethodGet, aboutPath)
-		if err != nil {
-			generator.ExitWithError("SEMP call failed. " + err.Error())
-		}
-		brokerSempVersion := result["sempVersion"].(string)
-		brokerPlatform := result["platform"].(string)
-		if !skipApiCheck && brokerPlatform != generated.Platform {
-			generator.ExitWithError(fmt.Sprintf("Broker platform \"%s\" does not match generator supported platform: %s", BrokerPlatformName[brokerPlatform], BrokerPlatformName[generated.Platform]))
+		generator.LogCLIInfo(fmt.Sprintf("Attempting config generation for object and its child-objects: %s, identifier: %s, destination file: %s\n", brokerObjectType, providerSpecificIdentifier, fileName))
+
+		brokerResourceTerraformName, brokerResourceName := parseResourceAddress(brokerObjectType)
+		if err := generator.GenerateAll(cliParams, cmd.Context(), cliClient, brokerResourceTerraformName, brokerResourceName, providerSpecificIdentifier, fileName); err != nil {
+			generator.ExitWithError("\nError: " + err.Error() + "\n\n")
 		}
-		generator.LogCLIInfo("Connection successful.")
-		generator.LogCLIInfo(fmt.Sprintf("Broker SEMP version is %s, Generator SEMP version is %s", brokerSempVersion, generated.SempVersion))
 
-		generator.LogCLIInfo(fmt.Sprintf("Attempting config generation for object and its child-objects: %s, identifier: %s, destination file: %s\n", brokerObjectType, providerSpecificIdentifier, fileName))
+		os.Exit(0)
+	},
+}
 
-		// Extract and verify parameters
-		if strings.Count(brokerObjectType, ".") != 1 {
-			generator.ExitWithError("\nError: Terraform resource address is not in correct format. Should be in the format <resource_type>.<resource_name>\n\n")
+func generateFromSnapshot(cmd *cobra.Command, cliParams generator.CliParams, snapshotFileName string) {
+	flags := cmd.Flags()
+	if flags.NArg() < 2 {
+		// Print the help message if the required arguments are not provided
+		_ = cmd.Help()
+		os.Exit(1)
+	}
+	cliParams, err := generator.UpdateOfflineCliParamsWithEnv(cliParams)
+	if err != nil {
+		generator.ExitWithError("\nError: " + err.Error() + "\n\n")
+	}
+	brokerObjectType := flags.Arg(0)
+	fileName := terraformFileName(cmd, cliParams, flags.Arg(1))
+	snapshot, err := generator.ReadSnapshot(snapshotFileName)
+	if err != nil {
+		generator.ExitWithError("Failed to read snapshot, " + err.Error())
+	}
+	generator.LogCLIInfo(fmt.Sprintf("Snapshot taken %s from broker SEMP version %v, Generator SEMP version is %s", snapshot.CreatedAt.Format(time.RFC3339), snapshot.About["sempVersion"], generated.SempVersion))
+	generator.LogCLIInfo(fmt.Sprintf("Attempting config generation for object and its child-objects from snapshot: %s, identifier: %s, destination file: %s\n", brokerObjectType, snapshot.Identifier, fileName))
+	brokerResourceTerraformName, brokerResourceName := parseResourceAddress(brokerObjectType)
+	if err := generator.GenerateAllFromSnapshot(cliParams, snapshot, brokerResourceTerraformName, brokerResourceName, fileName); err != nil {
+		generator.ExitWithError("\nError: " + err.Error() + "\n\n")
+	}
+}
+
+// cliParamsFromFlags returns the params set by flags, flags not defined for the command are left unset
+func cliParamsFromFlags(flags *pflag.FlagSet) generator.CliParams {
+	cliParams := generator.CliParams{}
+	if flags.Changed("url") {
+		if url, err := flags.GetString("url"); err == nil {
+			cliParams.Url = &url
+		}
+	}
+	if flags.Changed("username") {
+		if username, err := flags.GetString("username"); err == nil {
+			cliParams.Username = &username
+		}
+	}
+	if flags.Changed("password") {
+		if password, err := flags.GetString("password"); err == nil {
+			cliParams.Password = &password
+		}
+	}
+	if flags.Changed("bearer_token") {
+		if bearerToken, err := flags.GetString("bearer_token"); err == nil {
+			cliParams.Bearer_token = &bearerToken
+		}
+	}
+	if flags.Changed("oauth_token_url") {
+		if oauthTokenUrl, err := flags.GetString("oauth_token_url"); err == nil {
+			cliParams.Oauth_token_url = &oauthTokenUrl
+		}
+	}
+	if flags.Changed("oauth_client_id") {
+		if oauthClientId, err := flags.GetString("oauth_client_id"); err == nil {
+			cliParams.Oauth_client_id = &oauthClientId
+		}
+	}
+	if flags.Changed("oauth_client_secret") {
+		if oauthClientSecret, err := flags.GetString("oauth_client_secret"); err == nil {
+			cliParams.Oauth_client_secret = &oauthClientSecret
+		}
+	}
+	if flags.Changed("oauth_scopes") {
+		if oauthScopes, err := flags.GetString("oauth_scopes"); err == nil {
+			cliParams.Oauth_scopes = &oauthScopes
+		}
+	}
+	if flags.Changed("client_certificate") {
+		if clientCertificate, err := flags.GetString("client_certificate"); err == nil {
+			cliParams.Client_certificate = &clientCertificate
+		}
+	}
+	if flags.Changed("client_certificate_file") {
+		if clientCertificateFile, err := flags.GetString("client_certificate_file"); err == nil {
+			cliParams.Client_certificate_file = &clientCertificateFile
 		}
-		brokerResourceType := strings.Split(brokerObjectType, ".")[0]
-		brokerResourceName := strings.Split(brokerObjectType, ".")[1]
-		if !generator.IsValidTerraformIdentifier(brokerResourceName) {
-			generator.ExitWithError(fmt.Sprintf("\nError: Resource name %s in the Terraform resource address is not a valid Terraform identifier\n\n", brokerResourceName))
+	}
+	if flags.Changed("client_private_key") {
+		if clientPrivateKey, err := flags.GetString("client_private_key"); err == nil {
+			cliParams.Client_private_key = &clientPrivateKey
 		}
+	}
+	if flags.Changed("client_private_key_file") {
+		if clientPrivateKeyFile, err := flags.GetString("client_private_key_file"); err == nil {
+			cliParams.Client_private_key_file = &clientPrivateKeyFile
+		}
+	}
+	if flags.Changed("retries") {
+		if retries, err := flags.GetInt64("retries"); err == nil {
+			cliParams.Retries = &retries
+		}
+	}
+	if flags.Changed("retry_min_interval") {
+		if retryMinInterval, err := flags.GetDuration("retry_min_interval"); err == nil {
+			cliParams.Request_min_interval = &retryMinInterval
+		}
+	}
+	if flags.Changed("retry_max_interval") {
+		if retryMaxInterval, err := flags.GetDuration("retry_max_interval"); err == nil {
+			cliParams.Retry_max_interval = &retryMaxInterval
+		}
+	}
+	if flags.Changed("request_timeout_duration") {
+		if requestTimeoutDuration, err := flags.GetDuration("request_timeout_duration"); err == nil {
+			cliParams.Request_timeout_duration = &requestTimeoutDuration
+		}
+	}
+	if flags.Changed("request_min_interval") {
+		if requestMinInterval, err := flags.GetDuration("request_min_interval"); err == nil {
+			cliParams.Request_min_interval = &requestMinInterval
+		}
+	}
+	if flags.Changed("insecure_skip_verify") {
+		if insecureSkipVerify, err := flags.GetBool("insecure_skip_verify"); err == nil {
+			cliParams.Insecure_skip_verify = &insecureSkipVerify
+		}
+	}
+	if flags.Changed("ca_certificate") {
+		if caCertificate, err := flags.GetString("ca_certificate"); err == nil {
+			cliParams.Ca_certificate = &caCertificate
+		}
+	}
+	if flags.Changed("ca_bundle_file") {
+		if caBundleFile, err := flags.GetString("ca_bundle_file"); err == nil {
+			cliParams.Ca_bundle_file = &caBundleFile
+		}
+	}
+	if flags.Changed("tls_server_name") {
+		if tlsServerName, err := flags.GetString("tls_server_name"); err == nil {
+			cliParams.Tls_server_name = &tlsServerName
+		}
+	}
+	if flags.Changed("skip_api_check") {
+		if skipApiCheck, err := flags.GetBool("skip_api_check"); err == nil {
+			cliParams.Skip_api_check = &skipApiCheck
+		}
+	}
+	if flags.Changed("import_blocks") {
+		if importBlocks, err := flags.GetBool("import_blocks"); err == nil {
+			cliParams.Import_blocks = &importBlocks
+		}
+	}
+	if flags.Changed("module") {
+		if module, err := flags.GetBool("module"); err == nil {
+			cliParams.Module = &module
+		}
+	}
+	if flags.Changed("module_variables") {
+		if moduleVariables, err := flags.GetString("module_variables"); err == nil {
+			cliParams.Module_variables = &moduleVariables
+		}
+	}
+	if flags.Changed("include_type") {
+		if includeType, err := flags.GetStringArray("include_type"); err == nil {
+			cliParams.Include_type = &includeType
+		}
+	}
+	if flags.Changed("exclude_type") {
+		if excludeType, err := flags.GetStringArray("exclude_type"); err == nil {
+			cliParams.Exclude_type = &excludeType
+		}
+	}
+	if flags.Changed("include_name") {
+		if includeName, err := flags.GetStringArray("include_name"); err == nil {
+			cliParams.Include_name = &includeName
+		}
+	}
+	if flags.Changed("exclude_name") {
+		if excludeName, err := flags.GetStringArray("exclude_name"); err == nil {
+			cliParams.Exclude_name = &excludeName
+		}
+	}
+	if flags.Changed("parallelism") {
+		if parallelism, err := flags.GetInt64("parallelism"); err == nil {
+			cliParams.Parallelism = &parallelism
+		}
+	}
+	if flags.Changed("output_format") {
+		if outputFormat, err := flags.GetString("output_format"); err == nil {
+			cliParams.Output_format = &outputFormat
+		}
+	}
+	return cliParams
+}
 
-		brokerResourceTerraformName := strings.ReplaceAll(brokerResourceType, "solacebroker_", "")
-		generator.GenerateAll(cliParams, cmd.Context(), cliClient, brokerResourceTerraformName, brokerResourceName, providerSpecificIdentifier, fileName)
+// connectToBroker creates the SEMP client and confirms the connection and SEMP version, returning the about
+// information of the broker
+func connectToBroker(cmd *cobra.Command, cliParams generator.CliParams) (*semp.Client, map[string]any) {
+	cliClient, err := client.CliClient(cliParams)
+	if err != nil {
+		generator.ExitWithError("Error creating SEMP Client, " + err.Error())
+	}
 
-		os.Exit(0)
-	},
+	skipApiCheck := *cliParams.Skip_api_check
+	//Confirm SEMP version and connection via client
+	aboutPath := "/about/api"
+	result, err := cliClient.RequestWithoutBody(cmd.Context(), http.MethodGet, aboutPath)
+	if err != nil {
+		generator.ExitWithError("SEMP call failed. " + err.Error())
+	}
+	brokerSempVersion := result["sempVersion"].(string)
+	brokerPlatform := result["platform"].(string)
+	if !skipApiCheck && brokerPlatform != generated.Platform {
+		generator.ExitWithError(fmt.Sprintf("Broker platform \"%s\" does not match generator supported platform: %s", BrokerPlatformName[brokerPlatform], BrokerPlatformName[generated.Platform]))
+	}
+	generator.LogCLIInfo("Connection successful.")
+	generator.LogCLIInfo(fmt.Sprintf("Broker SEMP version is %s, Generator SEMP version is %s", brokerSempVersion, generated.SempVersion))
+	return cliClient, result
+}
+
+// terraformFileName verifies the file name argument and output format, adding the .tf or .tf.json extension unless
+// the output is a directory
+func terraformFileName(cmd *cobra.Command, cliParams generator.CliParams, fileName string) string {
+	if len(fileName) == 0 {
+		generator.LogCLIError("\nError: Terraform file name not specified.\n\n")
+		_ = cmd.Help()
+		os.Exit(1)
+	}
+	extension := ".tf"
+	switch *cliParams.Output_format {
+	case "hcl":
+	case "json":
+		extension = ".tf.json"
+	default:
+		generator.ExitWithError(fmt.Sprintf("\nError: Unsupported output format %s, must be hcl or json\n\n", *cliParams.Output_format))
+	}
+
+	if !*cliParams.Module && !generator.IsDirectoryOutput(fileName) && !strings.HasSuffix(fileName, extension) {
+		fileName = strings.TrimSuffix(fileName, ".tf") + extension
+	}
+	return fileName
+}
+
+// parseResourceAddress verifies a Terraform resource address and returns the resource type without the
+// "solacebroker_" prefix and the resource name
+func parseResourceAddress(brokerObjectType string) (string, string) {
+	if strings.Count(brokerObjectType, ".") != 1 {
+		generator.ExitWithError("\nError: Terraform resource address is not in correct format. Should be in the format <resource_type>.<resource_name>\n\n")
+	}
+	brokerResourceType := strings.Split(brokerObjectType, ".")[0]
+	brokerResourceName := strings.Split(brokerObjectType, ".")[1]
+	if !generator.IsValidTerraformIdentifier(brokerResourceName) {
+		generator.ExitWithError(fmt.Sprintf("\nError: Resource name %s in the Terraform resource address is not a valid Terraform identifier\n\n", brokerResourceName))
+	}
+
+	return strings.ReplaceAll(brokerResourceType, "solacebroker_", ""), brokerResourceName
 }
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
 	generateCmd.PersistentFlags().String("url", "http://localhost:8080", "Broker base URL, for example https://mybroker.example.org:<semp-service-port>")
-	generateCmd.PersistentFlags().String("username", "", "Basic authentication username")
-	generateCmd.PersistentFlags().String("password", "", "Basic authentication password")
-	generateCmd.PersistentFlags().String("bearer_token", "", "Bearer token for authentication")
-	generateCmd.PersistentFlags().Int64("retries", semp.DefaultRetries, "Retries")
-	generateCmd.PersistentFlags().Duration("retry_min_interval", semp.DefaultRetryMinInterval, "Minimum retry interval")
-	generateCmd.PersistentFlags().Duration("retry_max_interval", semp.DefaultRetryMaxInterval, "Maximum retry interval")
-	generateCmd.PersistentFlags().Duration("request_timeout_duration", semp.DefaultRequestTimeout, "Request timeout duration")
-	generateCmd.PersistentFlags().Duration("request_min_interval", semp.DefaultRequestInterval, "Minimum request interval")
-	generateCmd.PersistentFlags().Bool("insecure_skip_verify", false, "Disable validation of server SSL certificates")
-	generateCmd.PersistentFlags().Bool("skip_api_check", false, "Disable validation of the broker SEMP API")
+	addConnectionFlags(generateCmd)
+	generateCmd.PersistentFlags().Bool("import_blocks", false, "Also generate an import block for each resource, so that \"terraform plan\" can import the existing objects")
+	generateCmd.PersistentFlags().Bool("module", false, "Generate a reusable Terraform module, with the identifiers of the specified object as input variables")
+	generateCmd.PersistentFlags().String("module_variables", "", "Comma-separated <name>=<value> rules, parameterizing values in a generated module by input variables")
+	generateCmd.PersistentFlags().StringArray("include_type", nil, "Only generate child objects of resource types matching the pattern, repeatable")
+	generateCmd.PersistentFlags().StringArray("exclude_type", nil, "Skip child objects of resource types matching the pattern and their children, repeatable")
+	generateCmd.PersistentFlags().StringArray("include_name", nil, "Only generate child objects with names matching the [<type pattern>=]<name pattern>, repeatable")
+	generateCmd.PersistentFlags().StringArray("exclude_name", nil, "Skip child objects with names matching the [<type pattern>=]<name pattern> and their children, repeatable")
+	generateCmd.PersistentFlags().Int64("parallelism", 1, "Maximum number of concurrent SEMP requests to fetch child objects")
+	generateCmd.PersistentFlags().String("from_snapshot", "", "Generate from a snapshot file taken by the snapshot command instead of the broker")
+	generateCmd.PersistentFlags().String("output_format", "hcl", "Syntax of the generated configuration, hcl or json for the Terraform JSON configuration syntax")
+}
+
+// addConnectionFlags adds the flags to connect to the broker except the URL, which mirror the provider configuration
+func addConnectionFlags(command *cobra.Command) {
+	command.PersistentFlags().String("username", "", "Basic authentication username")
+	command.PersistentFlags().String("password", "", "Basic authentication password")
+	command.PersistentFlags().String("bearer_token", "", "Bearer token for authentication")
+	command.PersistentFlags().String("oauth_token_url", "", "OAuth2 token endpoint URL to obtain bearer tokens from")
+	command.PersistentFlags().String("oauth_client_id", "", "OAuth2 client ID")
+	command.PersistentFlags().String("oauth_client_secret", "", "OAuth2 client secret")
+	command.PersistentFlags().String("oauth_scopes", "", "Space-separated OAuth2 scopes to request")
+	command.PersistentFlags().String("client_certificate", "", "PEM encoded client certificate for client certificate authentication")
+	command.PersistentFlags().String("client_certificate_file", "", "File containing the PEM encoded client certificate for client certificate authentication")
+	command.PersistentFlags().String("client_private_key", "", "PEM encoded private key of the client certificate")
+	command.PersistentFlags().String("client_private_key_file", "", "File containing the PEM encoded private key of the client certificate")
+	command.PersistentFlags().Int64("retries", semp.DefaultRetries, "Retries")
+	command.PersistentFlags().Duration("retry_min_interval", semp.DefaultRetryMinInterval, "Minimum retry interval")
+	command.PersistentFlags().Duration("retry_max_interval", semp.DefaultRetryMaxInterval, "Maximum retry interval")
+	command.PersistentFlags().Duration("request_timeout_duration", semp.DefaultRequestTimeout, "Request timeout duration")
+	command.PersistentFlags().Duration("request_min_interval", semp.DefaultRequestInterval, "Minimum request interval")
+	command.PersistentFlags().Bool("insecure_skip_verify", false, "Disable validation of server SSL certificates")
+	command.PersistentFlags().String("ca_certificate", "", "PEM encoded CA certificates to trust for the broker server certificate")
+	command.PersistentFlags().String("ca_bundle_file", "", "File containing PEM encoded CA certificates to trust for the broker server certificate")
+	command.PersistentFlags().String("tls_server_name", "", "Server name to verify the broker server certificate against")
+	command.PersistentFlags().Bool("skip_api_check", false, "Disable validation of the broker SEMP API")
 }
@@ -19,13 +19,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	internalbroker "terraform-provider-solacebroker/internal/broker"
 	"terraform-provider-solacebroker/internal/broker/generated"
-	"terraform-provider-solacebroker/internal/semp"
 )
 
 type IdentifyingAttribute struct {
@@ -39,16 +41,77 @@ type BrokerObjectInstanceInfo struct {
 	identifyingAttributes IdentifyingAttributes
 }
 
-var rootBrokerObjectPathTemplate string
-var rootBrokerObjectResourceName string
-var cachedResources map[string]interface{}
-var brokerResources []map[string]ResourceConfig
-var variables map[string]VariableConfig
+// brokerConfig is the generated configuration of a broker object and its child objects
+type brokerConfig struct {
+	resources       []map[string]ResourceConfig // in the order the objects were found
+	objects         []resourceObject            // the objects of the resources, in the same order
+	variables       map[string]VariableConfig
+	filteredTypes   []BrokerObjectType // resource types skipped by a type filter
+	filteredObjects []string           // objects skipped by a name filter, with their type
+	warnings        []string
+	warningsLock    sync.Mutex // warnings are also added while fetching concurrently
+	log             io.Writer  // receives the warnings
+}
+
+// warn logs a warning and adds it to the config, unless it has already been added
+func (config *brokerConfig) warn(warning string) {
+	config.warningsLock.Lock()
+	defer config.warningsLock.Unlock()
+	if slices.Contains(config.warnings, warning) {
+		return
+	}
+	config.warnings = append(config.warnings, warning)
+	logInfo(config.log, warning+"\n")
+}
+
+// resourceObject is the broker object a resource was generated from
+type resourceObject struct {
+	brokerObjectType      BrokerObjectType
+	identifyingAttributes IdentifyingAttributes
+	result                map[string]any
+}
+
+// crawlOptions control which broker objects are fetched and how
+type crawlOptions struct {
+	filters                  *Filters  // optional, restricts the child objects to fetch
+	parallelism              int       // the maximum number of concurrent SEMP requests
+	includeSystemProvisioned bool      // also fetch the child objects of system provisioned objects
+	log                      io.Writer // receives progress messages and warnings, discarded if not set
+}
+
+// fetchedCollection holds the instances of a broker object type fetched for one parent object
+type fetchedCollection struct {
+	brokerObjectType BrokerObjectType
+	instances        []*fetchedInstance
+}
+
+// fetchedInstance is a broker object instance fetched from the broker, with the collections of its child objects
+type fetchedInstance struct {
+	result                map[string]any
+	identifyingAttributes IdentifyingAttributes
+	name                  string // the values of the object's own identifying attributes
+	systemProvisioned     bool   // skipped as system provisioned
+	filtered              bool   // skipped by a name filter, its child objects have not been fetched
+	children              map[BrokerObjectType]*fetchedCollection
+}
 
-func buildResourceTypeAndName(brokerObjectType BrokerObjectType, resourceInstancePathTemplate string, foundChildIndentifyingAttributes IdentifyingAttributes) (string, error) {
+// brokerConfigCrawler generates the configuration of a broker object and its child objects in two phases: first
+// fetching all objects from the broker, concurrently up to the parallelism, then processing them sequentially in the
+// order of the object hierarchy so that the output does not depend on the order of the responses.
+type brokerConfigCrawler struct {
+	client                       SempClient
+	options                      crawlOptions
+	rootBrokerObjectPathTemplate string
+	rootBrokerObjectResourceName string
+	// Only accessed while processing the fetched objects
+	resourceNames map[string]bool
+	config        *brokerConfig
+}
+
+func (c *brokerConfigCrawler) buildResourceTypeAndName(brokerObjectType BrokerObjectType, resourceInstancePathTemplate string, foundChildIndentifyingAttributes IdentifyingAttributes) (string, error) {
 	var resourceTypeAndName string
 	// Replace rootBrokerObjectPathTemplate part with rootBrokerObjectResourceName
-	convertedPath := strings.Replace(resourceInstancePathTemplate, rootBrokerObjectPathTemplate, rootBrokerObjectResourceName, 1)
+	convertedPath := strings.Replace(resourceInstancePathTemplate, c.rootBrokerObjectPathTemplate, c.rootBrokerObjectResourceName, 1)
 	// Split path by /
 	sections := strings.Split(convertedPath, "/")
 	resourceTypeAndName = sections[0]
@@ -68,14 +131,15 @@ func buildResourceTypeAndName(brokerObjectType BrokerObjectType, resourceInstanc
 	}
 	resourceTypeAndName = string(brokerObjectType) + " " + makeValidForTerraformIdentifier(resourceTypeAndName)
 	resourceTypeAndName = "solacebroker_" + resourceTypeAndName
-	// Loop while cachedResources has an entry with this key. Change the key appending __number until it is unique
+	// Loop while resourceNames has an entry with this key. Change the key appending __number until it is unique
 	i := 2
 	modifiedResourceTypeAndName := resourceTypeAndName
-	for cachedResources[modifiedResourceTypeAndName] != nil {
+	for c.resourceNames[modifiedResourceTypeAndName] {
 		modifiedResourceTypeAndName = resourceTypeAndName + "__" + fmt.Sprint(i)
 		i++
 	}
 	resourceTypeAndName = modifiedResourceTypeAndName
+	c.resourceNames[resourceTypeAndName] = true
 	return resourceTypeAndName, nil
 }
 
@@ -103,7 +167,7 @@ func getAllInstancesPathTemplate(brokerObjectType BrokerObjectType) (string, []s
 }
 
 func getInstancePathTemplate(brokerObjectType BrokerObjectType) (string, error) {
-	i, ok := DSLookup[brokerObjectType]
+	i, ok := dsLookup[brokerObjectType]
 	if !ok {
 		return "", fmt.Errorf("invalid broker object type")
 	}
@@ -129,6 +193,25 @@ func substituteVariables(template string, attributes IdentifyingAttributes, doEs
 	return result, nil
 }
 
+// Returns the identifier to import an instance of the brokerObjectType with, as expected by the provider: the values of
+// the identifying attributes in the order of the path template, each URL-encoded and separated by "/"
+func buildImportId(brokerObjectType BrokerObjectType, attributes IdentifyingAttributes) string {
+	pathTemplate := internalbroker.Entities[dsLookup[brokerObjectType]].PathTemplate
+	ordered := slices.Clone(attributes)
+	slices.SortStableFunc(ordered, func(a, b IdentifyingAttribute) int {
+		return strings.Index(pathTemplate, "{"+a.key+"}") - strings.Index(pathTemplate, "{"+b.key+"}")
+	})
+	var values []string
+	for _, identifyingAttribute := range ordered {
+		if !strings.Contains(pathTemplate, "{"+identifyingAttribute.key+"}") {
+			continue
+		}
+		// The provider accepts "," as well as "/" as separator, so it must be escaped in values, too
+		values = append(values, strings.ReplaceAll(url.PathEscape(identifyingAttribute.value), ",", "%2C"))
+	}
+	return strings.Join(values, "/")
+}
+
 func identifierToBrokerObjectAttributes(brokerObjectType BrokerObjectType, identifier string) (IdentifyingAttributes, error) {
 	pathTemplate, err := getInstancePathTemplate(brokerObjectType)
 	if err != nil {
@@ -159,14 +242,72 @@ func identifierToBrokerObjectAttributes(brokerObjectType BrokerObjectType, ident
 	return brokerObjectAttributes, nil
 }
 
-// Returns one instance of the brokerObjectType if identifier has been provided, otherwise all instances that match the parentIdentifyingAttributes
-// Communicates with the broker via the SEMP client to fetch the instances
-// As a side effect, it will also construct an identifier for an object instance, prep the attributes and cache the results for later use
-func getInstances(context context.Context, client semp.Client, brokerObjectType BrokerObjectType, identifier string, parent BrokerObjectInstanceInfo) ([]BrokerObjectInstanceInfo, error) {
-	var instances []BrokerObjectInstanceInfo
+// Main entry point to generate the config for a broker object and its child objects
+func fetchBrokerConfig(ctx context.Context, client SempClient, brokerObjectType BrokerObjectType, brokerResourceName string, identifier string, options crawlOptions) (*brokerConfig, error) {
+	c, err := newBrokerConfigCrawler(client, brokerObjectType, brokerResourceName, options)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := c.fetchCollection(ctx, brokerObjectType, identifier, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = c.processCollection(collection, BrokerObjectInstanceInfo{})
+	if err != nil {
+		return nil, err
+	}
+	return c.config, nil
+}
+
+func newBrokerConfigCrawler(client SempClient, brokerObjectType BrokerObjectType, brokerResourceName string, options crawlOptions) (*brokerConfigCrawler, error) {
+	// The parent-child relationship between broker objects is required to fetch the child objects
+	createBrokerObjectRelationships()
+	rootBrokerObjectPathTemplate, err := getInstancePathTemplate(brokerObjectType)
+	if err != nil {
+		return nil, err
+	}
+	if options.parallelism < 1 {
+		options.parallelism = 1
+	}
+	if options.log == nil {
+		options.log = io.Discard
+	}
+	return &brokerConfigCrawler{
+		client:                       client,
+		options:                      options,
+		rootBrokerObjectPathTemplate: rootBrokerObjectPathTemplate,
+		rootBrokerObjectResourceName: brokerResourceName,
+		resourceNames:                map[string]bool{},
+		config:                       &brokerConfig{variables: map[string]VariableConfig{}, log: options.log},
+	}, nil
+}
+
+// request fetches objects from the broker. The number of concurrent requests is limited by the number of fetch
+// workers, the client's rate limiter applies in addition.
+func (c *brokerConfigCrawler) request(ctx context.Context, requestPath string) ([]map[string]any, error) {
+	return c.client.RequestWithoutBodyForGenerator(ctx, generated.BasePath, http.MethodGet, requestPath, []map[string]any{})
+}
 
+// Fetches one instance of the brokerObjectType if identifier has been provided, otherwise all instances that match
+// the parentIdentifyingAttributes, and recursively the child objects of the instances
+func (c *brokerConfigCrawler) fetchCollection(ctx context.Context, brokerObjectType BrokerObjectType, identifier string, parentIdentifyingAttributes IdentifyingAttributes) (*fetchedCollection, error) {
+	collection, err := c.fetchInstances(ctx, brokerObjectType, identifier, parentIdentifyingAttributes)
+	if err != nil {
+		return nil, err
+	}
+	err = c.fetchChildCollections(ctx, collection)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// Fetches the instances of a collection, without their child objects
+func (c *brokerConfigCrawler) fetchInstances(ctx context.Context, brokerObjectType BrokerObjectType, identifier string, parentIdentifyingAttributes IdentifyingAttributes) (*fetchedCollection, error) {
+	logInfo(c.options.log, fmt.Sprintf("  ## Fetching config for resource %s\n", brokerObjectType))
+	collection := &fetchedCollection{brokerObjectType: brokerObjectType}
 	if identifier != "" {
-		// Return a single instance of the brokerObjectType that matches the identifier
+		// Fetch a single instance of the brokerObjectType that matches the identifier
 		// Determine the identifying attributes for the instance
 		instanceIdentifyingAttributes, err := identifierToBrokerObjectAttributes(brokerObjectType, identifier)
 		if err != nil {
@@ -181,131 +322,206 @@ func getInstances(context context.Context, client semp.Client, brokerObjectType
 		if err != nil {
 			return nil, err
 		}
-		results, err := client.RequestWithoutBodyForGenerator(context, generated.BasePath, http.MethodGet, requestPath, []map[string]any{})
+		results, err := c.request(ctx, requestPath)
 		if err != nil {
 			return nil, err
 		}
-		resourceTypeAndName, err := buildResourceTypeAndName(brokerObjectType, resourcePathTemplate, instanceIdentifyingAttributes)
-		if err != nil {
-			return nil, err
-		}
-		// create a resource config from results[0]
-		attributes := internalbroker.Entities[DSLookup[BrokerObjectType(brokerObjectType)]].Attributes
-		resourceValues, tfVariables, err := processSempResults(resourceTypeAndName, attributes, results, BrokerObjectInstanceInfo{})
-		if err != nil {
-			return nil, err
-		}
-		element := make(map[string]ResourceConfig)
-		element[resourceTypeAndName] = resourceValues[0]
-		brokerResources = append(brokerResources, element)
-		for key, value := range tfVariables {
-			variables[key] = value
-		}
-		instances = append(instances, BrokerObjectInstanceInfo{
-			resourceTypeAndName:   resourceTypeAndName,
+		collection.instances = append(collection.instances, &fetchedInstance{
+			result:                results[0],
 			identifyingAttributes: instanceIdentifyingAttributes,
 		})
-
 	} else {
 		// Query broker for all instances that match the parentIdentifyingAttributes
-		allResourcesPathTemplate, childIdentifierAttributes, resourceInstancePathTemplate, err := getAllInstancesPathTemplate(brokerObjectType)
+		allResourcesPathTemplate, childIdentifierAttributes, _, err := getAllInstancesPathTemplate(brokerObjectType)
 		if err != nil {
 			return nil, err
 		}
-		requestPath, err := substituteVariables(allResourcesPathTemplate, parent.identifyingAttributes, true)
+		requestPath, err := substituteVariables(allResourcesPathTemplate, parentIdentifyingAttributes, true)
 		if err != nil {
 			return nil, err
 		}
-		results, err := client.RequestWithoutBodyForGenerator(context, generated.BasePath, http.MethodGet, requestPath, []map[string]any{})
+		results, err := c.request(ctx, requestPath)
 		if err != nil {
 			// Fail except if the path is invalid - this means the generator SEMP schema is trying
 			// to fetch a resource that doesn't exist in an older broker
-			if !errors.Is(err, semp.ErrInvalidPath) {
+			if !errors.Is(err, ErrInvalidPath) {
 				return nil, err
 			}
-			LogCLIInfo(fmt.Sprintf("     Resource %s unknown on broker, check broker and generator SEMP versions\n", brokerObjectType))
+			c.config.warn(fmt.Sprintf("Resource %s unknown on broker, check broker and generator SEMP versions", brokerObjectType))
 		}
 		for _, result := range results {
-			// Extract the identifying attributes from the result
-			foundChildIndentifyingAttributes := parent.identifyingAttributes
-			skipAppendInstance := false
-			for _, childIdentifierAttribute := range childIdentifierAttributes {
-				// Skip system provisioned objects
-				if isSystemProvisionedAttribute(result[childIdentifierAttribute].(string)) {
-					// Workaround while waiting for SOL-117252
-					if string(brokerObjectType) == "msg_vpn_acl_profile" || string(brokerObjectType) == "msg_vpn_client_profile" || string(brokerObjectType) == "msg_vpn_client_username" || string(brokerObjectType) == "msg_vpn" {
-						skipAppendInstance = true
-						break
-					}
-				}
-				foundChildIndentifyingAttributes = append(foundChildIndentifyingAttributes, IdentifyingAttribute{key: childIdentifierAttribute, value: result[childIdentifierAttribute].(string)})
-			}
-			if !skipAppendInstance {
-				// also cache the results for later use
-				resourceTypeAndName, err := buildResourceTypeAndName(brokerObjectType, resourceInstancePathTemplate, foundChildIndentifyingAttributes)
-				if err != nil {
-					return nil, err
-				}
-				cachedResources[resourceTypeAndName] = "" // using cachedResources as a set
-				// create a resource config from result
-				var elems []map[string]interface{}
-				elems = append(elems, result)
-				attributes := internalbroker.Entities[DSLookup[BrokerObjectType(brokerObjectType)]].Attributes
-				resourceValues, tfVariables, err := processSempResults(resourceTypeAndName, attributes, elems, parent)
-				if err != nil {
-					return nil, err
-				}
-				element := make(map[string]ResourceConfig)
-				element[resourceTypeAndName] = resourceValues[0]
-				brokerResources = append(brokerResources, element)
-				for key, value := range tfVariables {
-					variables[key] = value
-				}
-				instances = append(instances, BrokerObjectInstanceInfo{
-					resourceTypeAndName:   resourceTypeAndName,
-					identifyingAttributes: foundChildIndentifyingAttributes,
-				})
-			}
+			collection.instances = append(collection.instances, c.newFetchedInstance(brokerObjectType, result, parentIdentifyingAttributes, childIdentifierAttributes))
 		}
+	}
+	return collection, nil
+}
 
+// Creates an instance from a SEMP result of a child object, determining its identifying attributes and whether it is
+// to be skipped
+func (c *brokerConfigCrawler) newFetchedInstance(brokerObjectType BrokerObjectType, result map[string]any, parentIdentifyingAttributes IdentifyingAttributes, childIdentifierAttributes []string) *fetchedInstance {
+	instance := &fetchedInstance{
+		result:                result,
+		identifyingAttributes: slices.Clone(parentIdentifyingAttributes),
 	}
-	return instances, nil
+	var names []string
+	for _, childIdentifierAttribute := range childIdentifierAttributes {
+		value, _ := result[childIdentifierAttribute].(string)
+		// Skip system provisioned objects
+		if internalbroker.IsSystemProvisionedObject(string(brokerObjectType), value) {
+			instance.systemProvisioned = true
+		}
+		instance.identifyingAttributes = append(instance.identifyingAttributes, IdentifyingAttribute{key: childIdentifierAttribute, value: value})
+		names = append(names, value)
+	}
+	// Apply the name filters to the values of the object's own identifying attributes
+	instance.name = strings.Join(names, ",")
+	instance.filtered = !instance.systemProvisioned && c.options.filters.SkipObject(brokerObjectType, instance.name)
+	return instance
 }
 
-// Main entry point to generate the config for a broker object
-func fetchBrokerConfig(context context.Context, client semp.Client, brokerObjectType BrokerObjectType, brokerResourceName string, identifier string) ([]map[string]ResourceConfig, map[string]VariableConfig, error) {
-	var err error
-	cachedResources = make(map[string]interface{})
-	variables = map[string]VariableConfig{}
-	rootBrokerObjectResourceName = brokerResourceName
-	rootBrokerObjectPathTemplate, err = getInstancePathTemplate(brokerObjectType)
-	if err != nil {
-		return nil, nil, err
+// fetchTask fetches the child objects of one type of an instance
+type fetchTask struct {
+	instance         *fetchedInstance
+	brokerObjectType BrokerObjectType
+}
+
+// fetchQueue holds the fetch tasks that are waiting for a worker
+type fetchQueue struct {
+	lock     sync.Mutex
+	ready    *sync.Cond // signalled when tasks are added or the last task completes
+	tasks    []fetchTask
+	pending  int // tasks queued or running
+	firstErr error
+	cancel   context.CancelFunc // stops the requests of the other workers on the first error
+}
+
+// Fetches the child objects of all instances in the collection, and recursively their child objects, with a fixed
+// number of workers up to the parallelism. Stops all requests on the first error.
+func (c *brokerConfigCrawler) fetchChildCollections(ctx context.Context, collection *fetchedCollection) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	queue := &fetchQueue{cancel: cancel}
+	queue.ready = sync.NewCond(&queue.lock)
+	c.queueChildCollections(queue, collection)
+	var wg sync.WaitGroup
+	for i := 0; i < c.options.parallelism; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.fetchWorker(ctx, queue)
+		}()
 	}
-	err = GenerateConfigForObjectInstances(context, client, brokerObjectType, identifier, BrokerObjectInstanceInfo{})
-	if err != nil {
-		return nil, nil, err
+	wg.Wait()
+	return queue.firstErr
+}
+
+// queueChildCollections adds a task for each child object type of the instances in the collection that is to be
+// fetched. Must be called with the queue locked once the workers have started.
+func (c *brokerConfigCrawler) queueChildCollections(queue *fetchQueue, collection *fetchedCollection) {
+	for _, instance := range collection.instances {
+		if instance.filtered || instance.systemProvisioned && !c.options.includeSystemProvisioned {
+			continue
+		}
+		instance.children = map[BrokerObjectType]*fetchedCollection{}
+		for _, subType := range brokerObjectRelationship[collection.brokerObjectType] {
+			if c.options.filters.SkipType(subType) {
+				continue
+			}
+			queue.tasks = append(queue.tasks, fetchTask{instance: instance, brokerObjectType: subType})
+			queue.pending++
+		}
 	}
-	return brokerResources, variables, nil
 }
 
-// This is a recursive function that generates the config for a broker object and its children
-// The entry point is the parent object with the identifier. For child objects the identifier is empty
-// It will call itself for each child object instance
-func GenerateConfigForObjectInstances(context context.Context, client semp.Client, brokerObjectType BrokerObjectType, identifier string, parentInstanceInfo BrokerObjectInstanceInfo) error {
-	// brokerObjectType is the current object type
-	// instances is the list of instances of the current object type
-	LogCLIInfo(fmt.Sprintf("  ## Fetching config for resource %s\n", brokerObjectType))
-	instances, err := getInstances(context, client, brokerObjectType, identifier, parentInstanceInfo)
+// fetchWorker runs the tasks of the queue until all of them have completed or one has failed
+func (c *brokerConfigCrawler) fetchWorker(ctx context.Context, queue *fetchQueue) {
+	queue.lock.Lock()
+	defer queue.lock.Unlock()
+	for {
+		for len(queue.tasks) == 0 && queue.pending > 0 && queue.firstErr == nil {
+			queue.ready.Wait()
+		}
+		if queue.pending == 0 || queue.firstErr != nil {
+			return
+		}
+		task := queue.tasks[0]
+		queue.tasks = queue.tasks[1:]
+		queue.lock.Unlock()
+		childCollection, err := c.fetchInstances(ctx, task.brokerObjectType, "", task.instance.identifyingAttributes)
+		queue.lock.Lock()
+		queue.pending--
+		if err != nil {
+			if queue.firstErr == nil {
+				queue.firstErr = err
+				queue.cancel()
+			}
+		} else {
+			task.instance.children[task.brokerObjectType] = childCollection
+			c.queueChildCollections(queue, childCollection)
+		}
+		queue.ready.Broadcast()
+	}
+}
+
+// Processes the fetched instances of a collection into resources and variables, then recursively their child
+// objects. The resources are added in the same order as the objects were found.
+func (c *brokerConfigCrawler) processCollection(collection *fetchedCollection, parent BrokerObjectInstanceInfo) error {
+	brokerObjectType := collection.brokerObjectType
+	resourceInstancePathTemplate, err := getInstancePathTemplate(brokerObjectType)
 	if err != nil {
 		return err
 	}
-	for i := 0; i < len(instances); i++ {
-		for _, subType := range BrokerObjectRelationship[brokerObjectType] {
-			// Will need to pass additional params like the parent name etc. so to construct the appropriate names
-			err := GenerateConfigForObjectInstances(context, client, subType, "", instances[i])
-			if err != nil {
-				return err
+	attributes := internalbroker.Entities[dsLookup[brokerObjectType]].Attributes
+	var processedInstances []*fetchedInstance
+	var instanceInfos []BrokerObjectInstanceInfo
+	for _, instance := range collection.instances {
+		if instance.systemProvisioned {
+			continue
+		}
+		if instance.filtered {
+			c.config.filteredObjects = append(c.config.filteredObjects, fmt.Sprintf("%s %s", brokerObjectType, instance.name))
+			continue
+		}
+		resourceTypeAndName, err := c.buildResourceTypeAndName(brokerObjectType, resourceInstancePathTemplate, instance.identifyingAttributes)
+		if err != nil {
+			return err
+		}
+		// create a resource config from the result
+		resourceValues, tfVariables, err := processSempResults(resourceTypeAndName, attributes, []map[string]any{instance.result}, parent)
+		if err != nil {
+			return err
+		}
+		resourceValues[0].ImportId = buildImportId(brokerObjectType, instance.identifyingAttributes)
+		element := make(map[string]ResourceConfig)
+		element[resourceTypeAndName] = resourceValues[0]
+		c.config.resources = append(c.config.resources, element)
+		c.config.objects = append(c.config.objects, resourceObject{
+			brokerObjectType:      brokerObjectType,
+			identifyingAttributes: instance.identifyingAttributes,
+			result:                instance.result,
+		})
+		for key, value := range tfVariables {
+			c.config.variables[key] = value
+		}
+		processedInstances = append(processedInstances, instance)
+		instanceInfos = append(instanceInfos, BrokerObjectInstanceInfo{
+			resourceTypeAndName:   resourceTypeAndName,
+			identifyingAttributes: instance.identifyingAttributes,
+		})
+	}
+	for i, instance := range processedInstances {
+		for _, subType := range brokerObjectRelationship[brokerObjectType] {
+			if c.options.filters.SkipType(subType) {
+				if !slices.Contains(c.config.filteredTypes, subType) {
+					c.config.filteredTypes = append(c.config.filteredTypes, subType)
+				}
+				continue
+			}
+			if childCollection, ok := instance.children[subType]; ok {
+				err := c.processCollection(childCollection, instanceInfos[i])
+				if err != nil {
+					return err
+				}
 			}
 		}
 	}
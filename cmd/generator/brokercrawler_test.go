@@ -0,0 +1,238 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+
+	"terraform-provider-solacebroker/internal/broker"
+	"terraform-provider-solacebroker/internal/broker/sempmock"
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func TestFetchBrokerConfigFromMockBroker(t *testing.T) {
+	mockBroker := sempmock.New(sempmock.PageSize(2))
+	defer mockBroker.Close()
+	for path, data := range map[string]map[string]any{
+		"/msgVpns/test":                               {"enabled": true},
+		"/msgVpns/test/queues/q1":                     {"maxBindCount": 10},
+		"/msgVpns/test/queues/q2":                     {},
+		"/msgVpns/test/queues/q3":                     {},
+		"/msgVpns/test/queues/q1/subscriptions/a%2Fb": {},
+		"/msgVpns/test/queues/q1/subscriptions/c%2Cd": {},
+		"/msgVpns/test/clientUsernames/user":          {"password": "secret"},
+	} {
+		if err := mockBroker.SetObject(path, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := semp.NewClient(mockBroker.URL+broker.SempDetail.BasePath, false, false, semp.Retries(0, 0, 0), semp.RequestLimits(semp.DefaultRequestTimeout, 0), semp.BasicAuth("admin", "admin"))
+	createBrokerObjectRelationships()
+	config, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{})
+	if err != nil {
+		t.Fatalf("fetchBrokerConfig() error = %v", err)
+	}
+	found := map[string]ResourceConfig{}
+	for _, resource := range config.resources {
+		for name, config := range resource {
+			found[name] = config
+		}
+	}
+	for _, name := range []string{
+		"solacebroker_msg_vpn test",
+		"solacebroker_msg_vpn_queue test_q1",
+		"solacebroker_msg_vpn_queue test_q2",
+		"solacebroker_msg_vpn_queue test_q3",
+		"solacebroker_msg_vpn_queue_subscription test_q1_a-b",
+		"solacebroker_msg_vpn_queue_subscription test_q1_c-d",
+		"solacebroker_msg_vpn_client_username test_user",
+	} {
+		if _, ok := found[name]; !ok {
+			t.Errorf("fetchBrokerConfig() did not generate %v", name)
+		}
+	}
+	if value := found["solacebroker_msg_vpn_queue test_q1"].ResourceAttributes["max_bind_count"].AttributeValue; value != "10" {
+		t.Errorf("fetchBrokerConfig() generated max_bind_count = %q, want 10", value)
+	}
+	if _, ok := found["solacebroker_msg_vpn_client_username test_user"].ResourceAttributes["password"]; ok {
+		t.Errorf("fetchBrokerConfig() generated a value for the sensitive password")
+	}
+	for name, wantImportId := range map[string]string{
+		"solacebroker_msg_vpn test":                           "test",
+		"solacebroker_msg_vpn_queue test_q1":                  "test/q1",
+		"solacebroker_msg_vpn_queue_subscription test_q1_a-b": "test/q1/a%2Fb",
+		"solacebroker_msg_vpn_queue_subscription test_q1_c-d": "test/q1/c%2Cd",
+		"solacebroker_msg_vpn_client_username test_user":      "test/user",
+	} {
+		if importId := found[name].ImportId; importId != wantImportId {
+			t.Errorf("fetchBrokerConfig() generated import identifier %q for %v, want %q", importId, name, wantImportId)
+		}
+	}
+}
+
+func TestFetchBrokerConfigWithFilters(t *testing.T) {
+	mockBroker := sempmock.New()
+	defer mockBroker.Close()
+	for path, data := range map[string]map[string]any{
+		"/msgVpns/test":                                     {},
+		"/msgVpns/test/queues/orders%2Fnew":                 {},
+		"/msgVpns/test/queues/orders%2Fnew/subscriptions/a": {},
+		"/msgVpns/test/queues/invoices":                     {},
+		"/msgVpns/test/queues/invoices/subscriptions/b":     {},
+		"/msgVpns/test/aclProfiles/acl":                     {},
+		"/msgVpns/test/mqttSessions/s,primary":              {},
+	} {
+		if err := mockBroker.SetObject(path, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := semp.NewClient(mockBroker.URL+broker.SempDetail.BasePath, false, false, semp.Retries(0, 0, 0), semp.RequestLimits(semp.DefaultRequestTimeout, 0), semp.BasicAuth("admin", "admin"))
+	createBrokerObjectRelationships()
+	tests := []struct {
+		name         string
+		includeTypes []string
+		excludeTypes []string
+		includeNames []string
+		excludeNames []string
+		want         []string
+		wantNot      []string
+	}{
+		{
+			"IncludeTypeAndName",
+			[]string{"msg_vpn_queue"},
+			nil,
+			[]string{"msg_vpn_queue=orders/*"},
+			nil,
+			[]string{"solacebroker_msg_vpn test", "solacebroker_msg_vpn_queue test_orders-new", "solacebroker_msg_vpn_queue_subscription test_orders-new_a"},
+			[]string{"solacebroker_msg_vpn_queue test_invoices", "solacebroker_msg_vpn_queue_subscription test_invoices_b", "solacebroker_msg_vpn_acl_profile test_acl"},
+		},
+		{
+			"ExcludeTypeAndName",
+			nil,
+			[]string{"msg_vpn_mqtt_*"},
+			nil,
+			[]string{"msg_vpn_queue=invoices"},
+			[]string{"solacebroker_msg_vpn_queue test_orders-new", "solacebroker_msg_vpn_acl_profile test_acl"},
+			[]string{"solacebroker_msg_vpn_queue test_invoices", "solacebroker_msg_vpn_queue_subscription test_invoices_b", "solacebroker_msg_vpn_mqtt_session test_s_primary"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := NewFilters(tt.includeTypes, tt.excludeTypes, tt.includeNames, tt.excludeNames)
+			if err != nil {
+				t.Fatalf("NewFilters() error = %v", err)
+			}
+			config, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{filters: filters})
+			if err != nil {
+				t.Fatalf("fetchBrokerConfig() error = %v", err)
+			}
+			found := map[string]bool{}
+			for _, resource := range config.resources {
+				for name := range resource {
+					found[name] = true
+				}
+			}
+			for _, name := range tt.want {
+				if !found[name] {
+					t.Errorf("fetchBrokerConfig() did not generate %v", name)
+				}
+			}
+			for _, name := range tt.wantNot {
+				if found[name] {
+					t.Errorf("fetchBrokerConfig() generated filtered %v", name)
+				}
+			}
+			if len(config.filteredObjects) != 1 {
+				t.Errorf("fetchBrokerConfig() filtered objects %v, want one", config.filteredObjects)
+			}
+		})
+	}
+}
+
+func TestFetchBrokerConfigParallel(t *testing.T) {
+	mockBroker := sempmock.New(sempmock.PageSize(5))
+	defer mockBroker.Close()
+	if err := mockBroker.SetObject("/msgVpns/test", map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 20; i++ {
+		// Names that map to the same Terraform identifier, to check that deduplicated names are deterministic
+		for _, queueName := range []string{fmt.Sprintf("q/%d", i), fmt.Sprintf("q.%d", i)} {
+			queuePath := "/msgVpns/test/queues/" + url.PathEscape(queueName)
+			if err := mockBroker.SetObject(queuePath, map[string]any{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := mockBroker.SetObject(queuePath+"/subscriptions/a", map[string]any{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	mockBroker.AddFault(sempmock.Fault{Path: "/msgVpns/test/queues/*", Latency: 5 * time.Millisecond})
+	client := semp.NewClient(mockBroker.URL+broker.SempDetail.BasePath, false, false, semp.Retries(0, 0, 0), semp.RequestLimits(semp.DefaultRequestTimeout, 0), semp.BasicAuth("admin", "admin"))
+	createBrokerObjectRelationships()
+	sequential, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{parallelism: 1})
+	if err != nil {
+		t.Fatalf("fetchBrokerConfig() error = %v", err)
+	}
+	if len(sequential.resources) != 81 {
+		t.Fatalf("fetchBrokerConfig() generated %d resources, want 81", len(sequential.resources))
+	}
+	for i := 0; i < 3; i++ {
+		parallel, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{parallelism: 8})
+		if err != nil {
+			t.Fatalf("fetchBrokerConfig() error = %v", err)
+		}
+		if !reflect.DeepEqual(parallel, sequential) {
+			t.Fatalf("fetchBrokerConfig() with parallelism generated different config")
+		}
+	}
+	// the number of goroutines is bounded by the parallelism, not by the number of objects
+	baseline := runtime.NumGoroutine()
+	done := make(chan struct{})
+	maxGoroutines := make(chan int)
+	go func() {
+		peak := 0
+		for {
+			select {
+			case <-done:
+				maxGoroutines <- peak
+				return
+			case <-time.After(time.Millisecond):
+				peak = max(peak, runtime.NumGoroutine())
+			}
+		}
+	}()
+	_, err = fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{parallelism: 2})
+	close(done)
+	if err != nil {
+		t.Fatalf("fetchBrokerConfig() error = %v", err)
+	}
+	if peak := <-maxGoroutines; peak > baseline+20 {
+		t.Errorf("fetchBrokerConfig() with parallelism 2 ran %d goroutines, %d before", peak, baseline)
+	}
+	mockBroker.AddFault(sempmock.Fault{Path: "/msgVpns/test/aclProfiles", HTTPStatus: http.StatusBadRequest, Status: sempmock.StatusNotAllowed, Description: "injected"})
+	if _, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{parallelism: 8}); err == nil {
+		t.Errorf("fetchBrokerConfig() with parallelism did not return the error of a request")
+	}
+}
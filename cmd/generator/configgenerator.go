@@ -17,11 +17,12 @@ package generator
 
 import (
 	"context"
+	"fmt"
 	"path"
 	"regexp"
 	"strings"
+	"sync"
 	internalbroker "terraform-provider-solacebroker/internal/broker"
-	"terraform-provider-solacebroker/internal/semp"
 )
 
 type BrokerObjectType string
@@ -33,6 +34,7 @@ type ResourceAttributeInfo struct {
 
 type ResourceConfig struct {
 	ResourceAttributes map[string]ResourceAttributeInfo // indexed by resource attribute name
+	ImportId           string                           // the provider-specific import identifier, empty for singletons
 }
 
 type VariableConfig struct {
@@ -42,61 +44,108 @@ type VariableConfig struct {
 }
 
 type ObjectInfo struct {
-	BasicAuthentication bool
-	FileName            string
-	BrokerResources     []map[string]string
-	Variables           map[string]VariableConfig
+	BasicAuthentication             bool
+	BearerTokenAuthentication       bool
+	OAuthAuthentication             bool
+	ClientCertificateAuthentication bool
+	FileName                        string
+	ImportBlocks                    bool
+	Module                          bool
+	OutputFormat                    string // "json" for the Terraform JSON configuration syntax, otherwise HCL
+	BrokerResources                 []map[string]string
+	Resources                       []map[string]ResourceConfig // the resources of BrokerResources, for the JSON syntax
+	ImportIds                       map[string]string           // HCL quoted import identifiers, indexed by resource type and name
+	Outputs                         map[string]string           // output values of a module, indexed by output name
+	Variables                       map[string]VariableConfig
 }
 
-var BrokerObjectRelationship = map[BrokerObjectType][]BrokerObjectType{}
-var DSLookup = map[BrokerObjectType]int{} // Helper to easily lookup an entity in internalbroker.Entities by name
+// The child object types of each broker object type and the index of its entity in internalbroker.Entities, built by
+// createBrokerObjectRelationships
+var brokerObjectRelationship = map[BrokerObjectType][]BrokerObjectType{}
+var dsLookup = map[BrokerObjectType]int{} // Helper to easily lookup an entity in internalbroker.Entities by name
 
-var ObjectNamesCount = map[string]int{}
-
-func GenerateAll(cliParams CliParams, context context.Context, cliClient *semp.Client, brokerResourceTerraformName string, brokerResourceName string, providerSpecificIdentifier string, fileName string) {
-	// First build the parent-child relationship between broker objects
-	CreateBrokerObjectRelationships()
-
-	// Check if the broker resource is supported
-	_, found := BrokerObjectRelationship[BrokerObjectType(brokerResourceTerraformName)]
-	if !found {
-		ExitWithError("\nError: Broker resource not found by terraform name : " + brokerResourceTerraformName + "\n\n")
+// GenerateAll generates the configuration of a broker object and its child objects as specified by the params and
+// writes it to the Terraform file or directory
+func GenerateAll(cliParams CliParams, context context.Context, cliClient SempClient, brokerResourceTerraformName string, brokerResourceName string, providerSpecificIdentifier string, fileName string) error {
+	config, err := ConfigFromCliParams(cliParams, brokerResourceTerraformName, brokerResourceName, providerSpecificIdentifier)
+	if err != nil {
+		return err
 	}
-
+	config.Log = LogWriter
 	// This will iterate all resources starting at brokerResourceTerraformName and genarete brokerResources and variables config for that and children
-	brokerResources, variables, err := fetchBrokerConfig(context, *cliClient, BrokerObjectType(brokerResourceTerraformName), brokerResourceName, providerSpecificIdentifier)
+	result, err := Generate(context, cliClient, config)
 	if err != nil {
-		ExitWithError("Failed to fetch broker config, " + err.Error())
+		return err
 	}
+	return writeResult(cliParams, result, fileName)
+}
 
-	// Postprocess brokerResources for dependencies
-	LogCLIInfo("Replacing hardcoded names of inter-object dependencies by references where required")
-	fixInterObjectDependencies(brokerResources)
-
-	// Prep to generate the Terraform file
-	object := &ObjectInfo{}
-	object.BrokerResources = resourcesToFormattedHCL(brokerResources)
-	object.Variables = variables
-	object.BasicAuthentication = (*cliParams.Username != "" && *cliParams.Bearer_token == "")
-	object.FileName = fileName
-	LogCLIInfo("Found all resources. Writing file " + fileName)
-
-	// Generate the Terraform file
-	err = GenerateTerraformFile(object)
+// GenerateAllFromSnapshot generates the configuration from a snapshot instead of the broker
+func GenerateAllFromSnapshot(cliParams CliParams, snapshot *Snapshot, brokerResourceTerraformName string, brokerResourceName string, fileName string) error {
+	config, err := ConfigFromCliParams(cliParams, brokerResourceTerraformName, brokerResourceName, "")
+	if err != nil {
+		return err
+	}
+	config.Log = LogWriter
+	result, err := GenerateFromSnapshot(snapshot, config)
 	if err != nil {
-		ExitWithError("Failed to write file, " + err.Error())
+		return err
+	}
+	return writeResult(cliParams, result, fileName)
+}
+
+// writeResult writes the generated config to the Terraform file or directory
+func writeResult(cliParams CliParams, result *Result, fileName string) error {
+	logFilterSummary(result)
+	if result.Module || IsDirectoryOutput(fileName) {
+		LogCLIInfo("Found all resources. Writing files to directory " + fileName)
+	} else {
+		LogCLIInfo("Found all resources. Writing file " + fileName)
+	}
+	if err := result.Write(fileName, WriteOptionsFromCliParams(cliParams)); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
 	}
 	LogCLIInfo(fileName + " created successfully.\n")
+	return nil
 }
 
-func CreateBrokerObjectRelationships() {
+// logFilterSummary lists the resource types and objects that were skipped by the filters
+func logFilterSummary(result *Result) {
+	if len(result.SkippedTypes) > 0 {
+		LogCLIInfo("Skipped resource types by filter: " + strings.Join(result.SkippedTypes, ", "))
+	}
+	if len(result.SkippedObjects) > 0 {
+		LogCLIInfo(fmt.Sprintf("Skipped %d objects and their child objects by filter:", len(result.SkippedObjects)))
+		for _, skippedObject := range result.SkippedObjects {
+			LogCLIInfo("  " + skippedObject)
+		}
+	}
+}
+
+var createBrokerObjectRelationshipsOnce sync.Once
+
+// createBrokerObjectRelationships builds brokerObjectRelationship and dsLookup from the entities. They are only built
+// once, so it is safe to call concurrently.
+func createBrokerObjectRelationships() {
+	createBrokerObjectRelationshipsOnce.Do(buildBrokerObjectRelationships)
+}
+
+// IsBrokerObjectType returns true if the type, without the "solacebroker_" prefix, is the resource type of a broker
+// object known to the provider
+func IsBrokerObjectType(brokerObjectType BrokerObjectType) bool {
+	createBrokerObjectRelationships()
+	_, found := brokerObjectRelationship[brokerObjectType]
+	return found
+}
+
+func buildBrokerObjectRelationships() {
 	// Loop through entities and build database
 	resourcesPathSignatureMap := map[string]string{}
 	e := internalbroker.Entities
 	for i, ds := range e {
 		// Create new entry for each resource
-		BrokerObjectRelationship[BrokerObjectType(ds.TerraformName)] = []BrokerObjectType{}
-		DSLookup[BrokerObjectType(ds.TerraformName)] = i
+		brokerObjectRelationship[BrokerObjectType(ds.TerraformName)] = []BrokerObjectType{}
+		dsLookup[BrokerObjectType(ds.TerraformName)] = i
 		// Build a signature for each resource
 		rex := regexp.MustCompile(`{[^\/]*}`)
 		signature := strings.TrimSuffix(strings.Replace(rex.ReplaceAllString(ds.PathTemplate, ""), "//", "/", -1), "/") // Find all parameters in path template enclosed in {} including multiple ones
@@ -114,72 +163,132 @@ func CreateBrokerObjectRelationships() {
 		parentSignature := path.Dir(signature)
 		if parentSignature != "." && parentSignature != "/" {
 			parentResource := resourcesPathSignatureMap[parentSignature]
-			BrokerObjectRelationship[BrokerObjectType(parentResource)] = append(BrokerObjectRelationship[BrokerObjectType(parentResource)], BrokerObjectType(ds.TerraformName))
+			brokerObjectRelationship[BrokerObjectType(parentResource)] = append(brokerObjectRelationship[BrokerObjectType(parentResource)], BrokerObjectType(ds.TerraformName))
 		}
 	}
 }
 
-func fixInterObjectDependencies(brokerResources []map[string]ResourceConfig) {
-	// this will modify the passed brokerResources object
-
-	//temporal hard coding dependency graph fix not available in SEMP API
-	InterObjectDependencies := map[string][]string{"solacebroker_msg_vpn_authorization_group": {"solacebroker_msg_vpn_client_profile", "solacebroker_msg_vpn_acl_profile"},
-		"solacebroker_msg_vpn_client_username":                            {"solacebroker_msg_vpn_client_profile", "solacebroker_msg_vpn_acl_profile"},
-		"solacebroker_msg_vpn_rest_delivery_point":                        {"solacebroker_msg_vpn_client_profile"},
-		"solacebroker_msg_vpn_acl_profile_client_connect_exception":       {"solacebroker_msg_vpn_acl_profile"},
-		"solacebroker_msg_vpn_acl_profile_publish_topic_exception":        {"solacebroker_msg_vpn_acl_profile"},
-		"solacebroker_msg_vpn_acl_profile_subscribe_share_name_exception": {"solacebroker_msg_vpn_acl_profile"},
-		"solacebroker_msg_vpn_acl_profile_subscribe_topic_exception":      {"solacebroker_msg_vpn_acl_profile"}}
-
-	ObjectNameAttributes := map[string]string{"solacebroker_msg_vpn_client_profile": "client_profile_name", "solacebroker_msg_vpn_acl_profile": "acl_profile_name"}
-
-	// Post-process brokerResources for dependencies
-
-	// For each resource check if there is any dependency
-	for _, resources := range brokerResources {
-		var resourceType string
-		// var resourceConfig ResourceConfig
-		for resourceKey := range resources {
-			resourceType = strings.Split(resourceKey, " ")[0]
-			resourceDependencies, exists := InterObjectDependencies[resourceType]
-			if !exists {
+// addInterObjectReferences replaces the names of other objects in attribute values by references to their resources,
+// so that Terraform creates the objects in the required order. The attributes are described by the References of the
+// attribute info. A referenced object must be in the same scope, that is have the same values for the identifying
+// attributes it shares with the referencing object. References that would create a cycle are not added.
+func addInterObjectReferences(config *brokerConfig) {
+	// this will modify the resources of the passed config
+	resourcesByType := map[BrokerObjectType][]int{}
+	for i, object := range config.objects {
+		resourcesByType[object.brokerObjectType] = append(resourcesByType[object.brokerObjectType], i)
+	}
+	references := map[int][]int{}
+	for i, object := range config.objects {
+		resourceKey, resourceConfig := resourceKeyAndConfig(config.resources[i])
+		for _, attr := range internalbroker.Entities[dsLookup[object.brokerObjectType]].Attributes {
+			if attr.References == nil {
+				continue
+			}
+			info, ok := resourceConfig.ResourceAttributes[attr.TerraformName]
+			value, isString := object.result[attr.SempName].(string)
+			// Only hardcoded names are replaced, not references to parent objects or variables
+			if !ok || !isString || value == "" || !strings.HasPrefix(info.AttributeValue, "\"") {
+				continue
+			}
+			referencedType := BrokerObjectType(attr.References.TerraformName)
+			referencedAttribute := findAttributeInfo(referencedType, attr.References.Attribute)
+			if referencedAttribute == nil {
 				continue
 			}
-			// Found a resource that has inter-object relationship
-			// fmt.Print("Found " + resourceKey + " with dependencies ")
-			// fmt.Println(resourceDependencies)
-			for _, dependency := range resourceDependencies {
-				nameAttribute := ObjectNameAttributes[dependency]
-				dependencyName := strings.Trim(resources[resourceKey].ResourceAttributes[nameAttribute].AttributeValue, "\"")
-				if dependencyName != "" {
-					// fmt.Println("   Dependency " + dependency + " name is " + dependencyName)
-					// Look up key for dependency with dependencyName - iterate all brokerResources
-					found := false
-					for _, r := range brokerResources {
-						for k := range r {
-							rName := strings.Split(k, " ")[0]
-							if rName != dependency {
-								continue
-							}
-							// Check the name of the found resource
-							if strings.Trim(r[k].ResourceAttributes[nameAttribute].AttributeValue, "\"") == dependencyName {
-								// fmt.Println("         Found " + k + " as suitable dependency")
-								// Replace hardcoded name by reference
-								newInfo := ResourceAttributeInfo{
-									AttributeValue: strings.Replace(k, " ", ".", -1) + "." + nameAttribute,
-									Comment:        resources[resourceKey].ResourceAttributes[nameAttribute].Comment,
-								}
-								resources[resourceKey].ResourceAttributes[nameAttribute] = newInfo
-								found = true
-								break
-							}
-						}
-						if found {
-							break
-						}
-					}
+			for _, j := range resourcesByType[referencedType] {
+				if !isReferencedObject(config.objects[j], object, referencedAttribute.SempName, value) {
+					continue
+				}
+				referencedKey, _ := resourceKeyAndConfig(config.resources[j])
+				if i == j || isDescendant(config.objects[j], object) || isReachable(references, j, i) {
+					config.warn(fmt.Sprintf("Keeping the name in %s.%s to avoid a reference cycle with %s", strings.ReplaceAll(resourceKey, " ", "."), attr.TerraformName, strings.ReplaceAll(referencedKey, " ", ".")))
+					break
 				}
+				references[i] = append(references[i], j)
+				resourceConfig.ResourceAttributes[attr.TerraformName] = ResourceAttributeInfo{
+					AttributeValue: strings.ReplaceAll(referencedKey, " ", ".") + "." + attr.References.Attribute,
+					Comment:        info.Comment,
+				}
+				break
+			}
+		}
+	}
+}
+
+func resourceKeyAndConfig(resource map[string]ResourceConfig) (string, ResourceConfig) {
+	for key, resourceConfig := range resource {
+		return key, resourceConfig
+	}
+	return "", ResourceConfig{}
+}
+
+func findAttributeInfo(brokerObjectType BrokerObjectType, terraformName string) *internalbroker.AttributeInfo {
+	if _, ok := dsLookup[brokerObjectType]; !ok {
+		return nil
+	}
+	for _, attr := range internalbroker.Entities[dsLookup[brokerObjectType]].Attributes {
+		if attr.TerraformName == terraformName {
+			return attr
+		}
+	}
+	return nil
+}
+
+// isReferencedObject returns true if the candidate has the referenced value and is in the scope of the referencing object
+func isReferencedObject(candidate resourceObject, referencing resourceObject, referencedSempName string, value string) bool {
+	for _, identifyingAttribute := range candidate.identifyingAttributes {
+		if identifyingAttribute.key == referencedSempName {
+			if identifyingAttribute.value != value {
+				return false
 			}
+			continue
+		}
+		if referencingValue, ok := identifyingAttributeValue(referencing.identifyingAttributes, identifyingAttribute.key); !ok || referencingValue != identifyingAttribute.value {
+			return false
+		}
+	}
+	return true
+}
+
+// isDescendant returns true if the object is a child object of the parent, directly or indirectly
+func isDescendant(object resourceObject, parent resourceObject) bool {
+	if len(object.identifyingAttributes) <= len(parent.identifyingAttributes) {
+		return false
+	}
+	for _, identifyingAttribute := range parent.identifyingAttributes {
+		if value, ok := identifyingAttributeValue(object.identifyingAttributes, identifyingAttribute.key); !ok || value != identifyingAttribute.value {
+			return false
+		}
+	}
+	return true
+}
+
+func identifyingAttributeValue(attributes IdentifyingAttributes, key string) (string, bool) {
+	for _, identifyingAttribute := range attributes {
+		if identifyingAttribute.key == key {
+			return identifyingAttribute.value, true
+		}
+	}
+	return "", false
+}
+
+// isReachable returns true if the resource at index from already references the resource at index to, directly or
+// indirectly
+func isReachable(references map[int][]int, from int, to int) bool {
+	visited := map[int]bool{}
+	pending := []int{from}
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == to {
+			return true
+		}
+		if visited[current] {
+			continue
 		}
+		visited[current] = true
+		pending = append(pending, references[current]...)
 	}
+	return false
 }
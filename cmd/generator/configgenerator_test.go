@@ -16,7 +16,12 @@
 package generator
 
 import (
+	"context"
 	"testing"
+
+	"terraform-provider-solacebroker/internal/broker"
+	"terraform-provider-solacebroker/internal/broker/sempmock"
+	"terraform-provider-solacebroker/internal/semp"
 )
 
 func TestCreateBrokerObjectRelationships(t *testing.T) {
@@ -27,14 +32,93 @@ func TestCreateBrokerObjectRelationships(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			CreateBrokerObjectRelationships()
-			if len(BrokerObjectRelationship) == 0 {
+			createBrokerObjectRelationships()
+			if len(brokerObjectRelationship) == 0 {
 				t.Errorf("Broker relationship not built ")
 			}
-			_, exist := BrokerObjectRelationship["msg_vpn"]
+			_, exist := brokerObjectRelationship["msg_vpn"]
 			if !exist {
 				t.Errorf("Broker relationship does not contain msgVPn relation")
 			}
 		})
 	}
 }
+
+func TestAttributeReferences(t *testing.T) {
+	createBrokerObjectRelationships()
+	count := 0
+	for _, entity := range broker.Entities {
+		for _, attr := range entity.Attributes {
+			if attr.References == nil {
+				continue
+			}
+			count++
+			referencedAttribute := findAttributeInfo(BrokerObjectType(attr.References.TerraformName), attr.References.Attribute)
+			if referencedAttribute == nil || !referencedAttribute.Identifying || attr.BaseType != broker.String {
+				t.Errorf("%s.%s references %v, which is not an identifying attribute", entity.TerraformName, attr.TerraformName, *attr.References)
+			}
+		}
+	}
+	if count == 0 {
+		t.Errorf("no attribute references found")
+	}
+}
+
+func TestAddInterObjectReferences(t *testing.T) {
+	mockBroker := sempmock.New()
+	defer mockBroker.Close()
+	for path, data := range map[string]map[string]any{
+		"/msgVpns/test":                     {},
+		"/msgVpns/test/aclProfiles/acl":     {},
+		"/msgVpns/test/bridges/bridge,auto": {},
+		"/msgVpns/test/bridges/bridge,auto/remoteMsgVpns/remote,192.168.0.1,eth0": {"queueBinding": "q", "unidirectionalClientProfile": "profile"},
+		"/msgVpns/test/clientProfiles/profile":                                    {},
+		"/msgVpns/test/clientUsernames/user":                                      {"aclProfileName": "acl", "clientProfileName": "profile"},
+		"/msgVpns/test/clientUsernames/other":                                     {"aclProfileName": "missing"},
+		"/msgVpns/test/queues/dmq":                                                {},
+		"/msgVpns/test/queues/q":                                                  {"deadMsgQueue": "dmq", "owner": "user"},
+		"/msgVpns/test/queues/a":                                                  {"deadMsgQueue": "b"},
+		"/msgVpns/test/queues/b":                                                  {"deadMsgQueue": "a"},
+		"/msgVpns/test/restDeliveryPoints/rdp":                                    {"clientProfileName": "profile"},
+		"/msgVpns/test/restDeliveryPoints/rdp/queueBindings/q":                    {},
+	} {
+		if err := mockBroker.SetObject(path, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := semp.NewClient(mockBroker.URL+broker.SempDetail.BasePath, false, false, semp.Retries(0, 0, 0), semp.RequestLimits(semp.DefaultRequestTimeout, 0), semp.BasicAuth("admin", "admin"))
+	createBrokerObjectRelationships()
+	config, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", crawlOptions{})
+	if err != nil {
+		t.Fatalf("fetchBrokerConfig() error = %v", err)
+	}
+	addInterObjectReferences(config)
+	found := map[string]ResourceConfig{}
+	for _, resource := range config.resources {
+		for name, resourceConfig := range resource {
+			found[name] = resourceConfig
+		}
+	}
+	for _, tt := range []struct {
+		resource  string
+		attribute string
+		want      string
+	}{
+		{"solacebroker_msg_vpn_bridge_remote_msg_vpn test_bridge_auto_remote_192-168-0-1_eth0", "queue_binding", "solacebroker_msg_vpn_queue.test_q.queue_name"},
+		{"solacebroker_msg_vpn_bridge_remote_msg_vpn test_bridge_auto_remote_192-168-0-1_eth0", "unidirectional_client_profile", "solacebroker_msg_vpn_client_profile.test_profile.client_profile_name"},
+		{"solacebroker_msg_vpn_client_username test_user", "acl_profile_name", "solacebroker_msg_vpn_acl_profile.test_acl.acl_profile_name"},
+		{"solacebroker_msg_vpn_client_username test_user", "client_profile_name", "solacebroker_msg_vpn_client_profile.test_profile.client_profile_name"},
+		{"solacebroker_msg_vpn_client_username test_other", "acl_profile_name", "\"missing\""},
+		{"solacebroker_msg_vpn_queue test_q", "dead_msg_queue", "solacebroker_msg_vpn_queue.test_dmq.queue_name"},
+		{"solacebroker_msg_vpn_queue test_q", "owner", "solacebroker_msg_vpn_client_username.test_user.client_username"},
+		{"solacebroker_msg_vpn_queue test_a", "dead_msg_queue", "solacebroker_msg_vpn_queue.test_b.queue_name"},
+		{"solacebroker_msg_vpn_queue test_b", "dead_msg_queue", "\"a\""},
+		{"solacebroker_msg_vpn_rest_delivery_point test_rdp", "client_profile_name", "solacebroker_msg_vpn_client_profile.test_profile.client_profile_name"},
+		{"solacebroker_msg_vpn_rest_delivery_point_queue_binding test_rdp_q", "queue_binding_name", "solacebroker_msg_vpn_queue.test_q.queue_name"},
+		{"solacebroker_msg_vpn_rest_delivery_point_queue_binding test_rdp_q", "rest_delivery_point_name", "solacebroker_msg_vpn_rest_delivery_point.test_rdp.rest_delivery_point_name"},
+	} {
+		if got := found[tt.resource].ResourceAttributes[tt.attribute].AttributeValue; got != tt.want {
+			t.Errorf("addInterObjectReferences() set %s %s = %s, want %s", tt.resource, tt.attribute, got, tt.want)
+		}
+	}
+}
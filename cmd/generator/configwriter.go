@@ -19,7 +19,9 @@ import (
 	"bytes"
 	"embed"
 	"os"
+	"path/filepath"
 	"strings"
+	internalbroker "terraform-provider-solacebroker/internal/broker"
 	"text/template"
 )
 
@@ -39,6 +41,7 @@ func init() {
 		"readHCLResourceName": func(slice []string, index int) string {
 			return slice[index]
 		},
+		"commentLines": commentLines,
 	}).Parse(string(terraformTemplateString))
 	if err != nil {
 		panic(err)
@@ -46,10 +49,129 @@ func init() {
 }
 
 func GenerateTerraformFile(terraformObjectInfo *ObjectInfo) error {
+	if terraformObjectInfo.OutputFormat == "json" {
+		return writeJSONSections(terraformObjectInfo.FileName, terraformObjectInfo, "terraform", "providerVariables", "provider", "variables", "resources")
+	}
 	var codeStream bytes.Buffer
 	err := terraformTemplate.Execute(&codeStream, terraformObjectInfo)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(terraformObjectInfo.FileName, codeStream.Bytes(), 0664)
+	content, err := formatHcl(terraformObjectInfo.FileName, codeStream.Bytes())
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(terraformObjectInfo.FileName, content, 0664)
+}
+
+// GenerateTerraformDirectory writes the configuration to a directory instead of a single file: the provider
+// configuration to providers.tf, all variables to variables.tf and the resources to one file per resource type.
+// As all files are in the same Terraform module, references between resources in different files still resolve.
+// For a module, the provider is not configured and the outputs are written to outputs.tf. In the JSON syntax, the files
+// have the .tf.json extension instead. Files of an earlier run that are not written again, for example of resource
+// types that are now filtered out, are removed so that Terraform doesn't load them; other files are left unchanged.
+func GenerateTerraformDirectory(terraformObjectInfo *ObjectInfo) error {
+	directory := terraformObjectInfo.FileName
+	if err := os.MkdirAll(directory, 0775); err != nil {
+		return err
+	}
+	writeTemplate, extension := writeTemplateSections, ".tf"
+	if terraformObjectInfo.OutputFormat == "json" {
+		writeTemplate, extension = writeJSONSections, ".tf.json"
+	}
+	written := map[string]bool{}
+	writeSections := func(fileName string, terraformObjectInfo *ObjectInfo, sections ...string) error {
+		written[filepath.Base(fileName)] = true
+		return writeTemplate(fileName, terraformObjectInfo, sections...)
+	}
+	if terraformObjectInfo.Module {
+		// A module only declares the provider it requires, the provider is configured by the calling module
+		if err := writeSections(filepath.Join(directory, "providers"+extension), terraformObjectInfo, "terraform"); err != nil {
+			return err
+		}
+		if err := writeSections(filepath.Join(directory, "variables"+extension), terraformObjectInfo, "variables"); err != nil {
+			return err
+		}
+		if err := writeSections(filepath.Join(directory, "outputs"+extension), terraformObjectInfo, "outputs"); err != nil {
+			return err
+		}
+	} else {
+		if err := writeSections(filepath.Join(directory, "providers"+extension), terraformObjectInfo, "terraform", "provider"); err != nil {
+			return err
+		}
+		if err := writeSections(filepath.Join(directory, "variables"+extension), terraformObjectInfo, "providerVariables", "variables"); err != nil {
+			return err
+		}
+	}
+	// Group the resources by type, keeping the order in which they were found
+	var resourceTypes []string
+	resourcesByType := map[string][]int{}
+	for i, resources := range terraformObjectInfo.BrokerResources {
+		for resourceTypeAndName := range resources {
+			resourceType := strings.Split(resourceTypeAndName, " ")[0]
+			if _, found := resourcesByType[resourceType]; !found {
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+			resourcesByType[resourceType] = append(resourcesByType[resourceType], i)
+		}
+	}
+	for _, resourceType := range resourceTypes {
+		resourceTypeInfo := *terraformObjectInfo
+		resourceTypeInfo.BrokerResources, resourceTypeInfo.Resources = nil, nil
+		for _, i := range resourcesByType[resourceType] {
+			resourceTypeInfo.BrokerResources = append(resourceTypeInfo.BrokerResources, terraformObjectInfo.BrokerResources[i])
+			if i < len(terraformObjectInfo.Resources) {
+				resourceTypeInfo.Resources = append(resourceTypeInfo.Resources, terraformObjectInfo.Resources[i])
+			}
+		}
+		fileName := filepath.Join(directory, strings.TrimPrefix(resourceType, "solacebroker_")+extension)
+		if err := writeSections(fileName, &resourceTypeInfo, "resources"); err != nil {
+			return err
+		}
+	}
+	return removeStaleFiles(directory, written)
+}
+
+// removeStaleFiles removes the files in the directory that the generator writes, in either syntax, but that have not
+// been written by this run
+func removeStaleFiles(directory string, written map[string]bool) error {
+	generatedNames := map[string]bool{"providers": true, "variables": true, "outputs": true}
+	for _, entity := range internalbroker.Entities {
+		generatedNames[entity.TerraformName] = true
+	}
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		baseName := strings.TrimSuffix(strings.TrimSuffix(name, ".json"), ".tf")
+		if !entry.Type().IsRegular() || written[name] || !generatedNames[baseName] || (!strings.HasSuffix(name, ".tf") && !strings.HasSuffix(name, ".tf.json")) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(directory, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTemplateSections writes the named sections of the Terraform template to a file, separated by empty lines
+func writeTemplateSections(fileName string, terraformObjectInfo *ObjectInfo, sections ...string) error {
+	var contents []string
+	for _, section := range sections {
+		var codeStream bytes.Buffer
+		err := terraformTemplate.ExecuteTemplate(&codeStream, section, terraformObjectInfo)
+		if err != nil {
+			return err
+		}
+		if content := strings.Trim(codeStream.String(), "\n"); content != "" {
+			contents = append(contents, content)
+		}
+	}
+	content, err := formatHcl(fileName, []byte(strings.Join(contents, "\n\n")+"\n"))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, content, 0664)
 }
@@ -15,7 +15,12 @@
 // limitations under the License.
 package generator
 
-import "testing"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestGenerateTerraformFile(t *testing.T) {
 	type args struct {
@@ -49,3 +54,190 @@ func TestGenerateTerraformFile(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateTerraformFileImportBlocks(t *testing.T) {
+	brokerResources := []map[string]ResourceConfig{
+		{"solacebroker_broker broker": {ResourceAttributes: map[string]ResourceAttributeInfo{}}},
+		{"solacebroker_msg_vpn_queue test_q1": {ResourceAttributes: map[string]ResourceAttributeInfo{}, ImportId: "test/q1"}},
+	}
+	tests := []struct {
+		name         string
+		importBlocks bool
+		want         []string
+		wantNot      []string
+	}{
+		{
+			"WithImportBlocks",
+			true,
+			[]string{"import {\n  to = solacebroker_msg_vpn_queue.test_q1\n  id = \"test/q1\"\n}"},
+			[]string{"to = solacebroker_broker.broker"},
+		},
+		{
+			"WithoutImportBlocks",
+			false,
+			nil,
+			[]string{"import {"},
+		},
+	}
+	formattedResources, err := resourcesToFormattedHCL(brokerResources)
+	if err != nil {
+		t.Fatalf("resourcesToFormattedHCL() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileName := t.TempDir() + "/config.tf"
+			err := GenerateTerraformFile(&ObjectInfo{
+				FileName:        fileName,
+				ImportBlocks:    tt.importBlocks,
+				BrokerResources: formattedResources,
+				ImportIds:       resourcesToImportIds(brokerResources),
+			})
+			if err != nil {
+				t.Fatalf("GenerateTerraformFile() error = %v", err)
+			}
+			content, _ := os.ReadFile(fileName)
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("GenerateTerraformFile() content does not contain %q:\n%s", want, content)
+				}
+			}
+			for _, wantNot := range tt.wantNot {
+				if strings.Contains(string(content), wantNot) {
+					t.Errorf("GenerateTerraformFile() content contains %q:\n%s", wantNot, content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTerraformDirectory(t *testing.T) {
+	brokerResources := []map[string]ResourceConfig{
+		{"solacebroker_msg_vpn test": {ResourceAttributes: map[string]ResourceAttributeInfo{"msg_vpn_name": {AttributeValue: "\"test\""}}, ImportId: "test"}},
+		{"solacebroker_msg_vpn_queue test_q1": {ResourceAttributes: map[string]ResourceAttributeInfo{"msg_vpn_name": {AttributeValue: "solacebroker_msg_vpn.test.msg_vpn_name"}}, ImportId: "test/q1"}},
+		{"solacebroker_msg_vpn_queue test_q2": {ResourceAttributes: map[string]ResourceAttributeInfo{"msg_vpn_name": {AttributeValue: "solacebroker_msg_vpn.test.msg_vpn_name"}}, ImportId: "test/q2"}},
+	}
+	directory := t.TempDir() + "/config/"
+	if !IsDirectoryOutput(directory) {
+		t.Fatalf("IsDirectoryOutput(%q) = false, want true", directory)
+	}
+	formattedResources, err := resourcesToFormattedHCL(brokerResources)
+	if err != nil {
+		t.Fatalf("resourcesToFormattedHCL() error = %v", err)
+	}
+	err = GenerateTerraformDirectory(&ObjectInfo{
+		BasicAuthentication: true,
+		FileName:            directory,
+		ImportBlocks:        true,
+		BrokerResources:     formattedResources,
+		ImportIds:           resourcesToImportIds(brokerResources),
+		Variables:           map[string]VariableConfig{"password": {Type: "string", Sensitive: true}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTerraformDirectory() error = %v", err)
+	}
+	tests := []struct {
+		fileName string
+		want     []string
+		wantNot  []string
+	}{
+		{"providers.tf", []string{"required_providers", "provider \"solacebroker\""}, []string{"variable \"", "resource \""}},
+		{"variables.tf", []string{"variable \"broker_url\"", "variable \"broker_password\"", "variable \"password\""}, []string{"provider \"", "resource \""}},
+		{"msg_vpn.tf", []string{"resource \"solacebroker_msg_vpn\" \"test\"", "id = \"test\""}, []string{"solacebroker_msg_vpn_queue\""}},
+		{"msg_vpn_queue.tf", []string{"resource \"solacebroker_msg_vpn_queue\" \"test_q1\"", "resource \"solacebroker_msg_vpn_queue\" \"test_q2\"", "solacebroker_msg_vpn.test.msg_vpn_name"}, []string{"resource \"solacebroker_msg_vpn\""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			content, err := os.ReadFile(directory + tt.fileName)
+			if err != nil {
+				t.Fatalf("file not generated: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("%v does not contain %q:\n%s", tt.fileName, want, content)
+				}
+			}
+			for _, wantNot := range tt.wantNot {
+				if strings.Contains(string(content), wantNot) {
+					t.Errorf("%v contains %q:\n%s", tt.fileName, wantNot, content)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTerraformDirectoryModule(t *testing.T) {
+	directory := t.TempDir()
+	err := GenerateTerraformDirectory(&ObjectInfo{
+		BasicAuthentication: true,
+		FileName:            directory,
+		Module:              true,
+		BrokerResources:     []map[string]string{{"solacebroker_msg_vpn myvpn": "\tmsg_vpn_name = var.msg_vpn_name\n"}},
+		Variables:           map[string]VariableConfig{"msg_vpn_name": {Type: "string", Default: "\"test\""}},
+		Outputs:             map[string]string{"msg_vpn_name": "solacebroker_msg_vpn.myvpn.msg_vpn_name"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTerraformDirectory() error = %v", err)
+	}
+	tests := []struct {
+		fileName string
+		want     string
+		wantNot  string
+	}{
+		{"providers.tf", "required_providers", "provider \"solacebroker\""},
+		{"variables.tf", "variable \"msg_vpn_name\"", "variable \"broker_url\""},
+		{"outputs.tf", "output \"msg_vpn_name\" {\n  value = solacebroker_msg_vpn.myvpn.msg_vpn_name\n}", "variable \""},
+		{"msg_vpn.tf", "msg_vpn_name = var.msg_vpn_name", "import {"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			content, err := os.ReadFile(directory + "/" + tt.fileName)
+			if err != nil {
+				t.Fatalf("file not generated: %v", err)
+			}
+			if !strings.Contains(string(content), tt.want) {
+				t.Errorf("%v does not contain %q:\n%s", tt.fileName, tt.want, content)
+			}
+			if strings.Contains(string(content), tt.wantNot) {
+				t.Errorf("%v contains %q:\n%s", tt.fileName, tt.wantNot, content)
+			}
+		})
+	}
+}
+
+func TestGenerateTerraformDirectoryRemovesStaleFiles(t *testing.T) {
+	directory := t.TempDir()
+	for _, fileName := range []string{"msg_vpn_queue.tf", "outputs.tf", "variables.tf.json", "main.tf", "msg_vpn_queue.tf.bak"} {
+		if err := os.WriteFile(filepath.Join(directory, fileName), []byte("# earlier run\n"), 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := GenerateTerraformDirectory(&ObjectInfo{
+		BasicAuthentication: true,
+		FileName:            directory,
+		BrokerResources:     []map[string]string{{"solacebroker_msg_vpn test": "\tmsg_vpn_name = \"test\"\n"}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTerraformDirectory() error = %v", err)
+	}
+	tests := []struct {
+		fileName string
+		want     bool
+	}{
+		{"providers.tf", true},
+		{"variables.tf", true},
+		{"msg_vpn.tf", true},
+		{"msg_vpn_queue.tf", false},
+		{"outputs.tf", false},
+		{"variables.tf.json", false},
+		{"main.tf", true},
+		{"msg_vpn_queue.tf.bak", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileName, func(t *testing.T) {
+			_, err := os.Stat(filepath.Join(directory, tt.fileName))
+			if exists := err == nil; exists != tt.want {
+				t.Errorf("%v exists = %v, want %v", tt.fileName, exists, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	internalbroker "terraform-provider-solacebroker/internal/broker"
+)
+
+// The kinds of changes of an object between two sources
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffSource is the configuration of a broker object and its child objects to compare, fetched from a broker or read
+// from a snapshot
+type DiffSource struct {
+	Name       string // the broker URL or snapshot file name
+	collection *fetchedCollection
+}
+
+// Diff lists the objects that differ between two sources, in the order of the object hierarchy
+type Diff struct {
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Objects []ObjectDiff `json:"objects"`
+}
+
+// ObjectDiff is an object that only exists in one of the sources, or whose attributes differ
+type ObjectDiff struct {
+	Change     string          `json:"change"`
+	ObjectType string          `json:"objectType"`           // the resource type, including the "solacebroker_" prefix
+	Identifier string          `json:"identifier"`           // the import identifier in the "from" source, or the "to" source for added objects
+	Attributes []AttributeDiff `json:"attributes,omitempty"` // the differing attributes of changed objects
+}
+
+// AttributeDiff is an attribute with different values in the sources. Values are as in the generated configuration,
+// nil if the attribute has its default value.
+type AttributeDiff struct {
+	Name string  `json:"name"`
+	From *string `json:"from"`
+	To   *string `json:"to"`
+}
+
+// DiffSourceFromBroker fetches the configuration of a broker object and its child objects, applying the filters of the
+// params
+func DiffSourceFromBroker(cliParams CliParams, context context.Context, client SempClient, brokerObjectType BrokerObjectType, identifier string, name string) (*DiffSource, error) {
+	options, err := diffCrawlOptions(cliParams, brokerObjectType)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newBrokerConfigCrawler(client, brokerObjectType, "", options)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := c.fetchCollection(context, brokerObjectType, identifier, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DiffSource{Name: name, collection: collection}, nil
+}
+
+// DiffSourceFromSnapshot reads the configuration of the object of a snapshot and its child objects, applying the
+// filters of the params
+func DiffSourceFromSnapshot(cliParams CliParams, snapshot *Snapshot, name string) (*DiffSource, error) {
+	options, err := diffCrawlOptions(cliParams, BrokerObjectType(snapshot.ObjectType))
+	if err != nil {
+		return nil, err
+	}
+	c, err := newBrokerConfigCrawler(nil, BrokerObjectType(snapshot.ObjectType), "", options)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := c.snapshotCollection(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &DiffSource{Name: name, collection: collection}, nil
+}
+
+// diffCrawlOptions returns the options to fetch a source with, as for generating with the params
+func diffCrawlOptions(cliParams CliParams, brokerObjectType BrokerObjectType) (crawlOptions, error) {
+	config, err := ConfigFromCliParams(cliParams, string(brokerObjectType), "", "")
+	if err != nil {
+		return crawlOptions{}, err
+	}
+	config.Log = LogWriter
+	_, options, err := config.crawlOptions()
+	return options, err
+}
+
+// DiffBrokerConfigs compares the objects of two sources. The specified objects are compared with each other, even if
+// their identifiers differ; child objects are matched by their own identifying attributes. Attributes are compared as
+// they would be generated, so attributes with default values are ignored, as well as write-only attributes.
+func DiffBrokerConfigs(from *DiffSource, to *DiffSource) (*Diff, error) {
+	if from.collection.brokerObjectType != to.collection.brokerObjectType {
+		return nil, fmt.Errorf("cannot compare a %s object with a %s object", from.collection.brokerObjectType, to.collection.brokerObjectType)
+	}
+	diff := &Diff{From: from.Name, To: to.Name, Objects: []ObjectDiff{}}
+	err := diff.addInstances(from.collection.brokerObjectType, from.collection.instances[0], to.collection.instances[0])
+	if err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// addInstances adds the differences of an object, which exists in at least one of the sources, and of its child objects
+func (d *Diff) addInstances(brokerObjectType BrokerObjectType, from *fetchedInstance, to *fetchedInstance) error {
+	objectDiff := ObjectDiff{ObjectType: "solacebroker_" + string(brokerObjectType)}
+	switch {
+	case to == nil:
+		objectDiff.Change = DiffRemoved
+		objectDiff.Identifier = buildImportId(brokerObjectType, from.identifyingAttributes)
+	case from == nil:
+		objectDiff.Change = DiffAdded
+		objectDiff.Identifier = buildImportId(brokerObjectType, to.identifyingAttributes)
+	default:
+		objectDiff.Change = DiffChanged
+		objectDiff.Identifier = buildImportId(brokerObjectType, from.identifyingAttributes)
+		attributeDiffs, err := diffAttributes(brokerObjectType, from, to)
+		if err != nil {
+			return err
+		}
+		objectDiff.Attributes = attributeDiffs
+	}
+	if objectDiff.Change != DiffChanged || len(objectDiff.Attributes) > 0 {
+		d.Objects = append(d.Objects, objectDiff)
+	}
+	for _, subType := range brokerObjectRelationship[brokerObjectType] {
+		fromChildren := comparableInstances(from, subType)
+		toChildren := comparableInstances(to, subType)
+		fromIndex := indexInstances(fromChildren)
+		toIndex := indexInstances(toChildren)
+		var names []string
+		for _, instance := range fromChildren {
+			names = append(names, instance.name)
+		}
+		for _, instance := range toChildren {
+			if fromIndex[instance.name] == nil {
+				names = append(names, instance.name)
+			}
+		}
+		for _, name := range names {
+			if err := d.addInstances(subType, fromIndex[name], toIndex[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// comparableInstances returns the child objects of a type of an instance, except the ones skipped by the generator
+func comparableInstances(instance *fetchedInstance, brokerObjectType BrokerObjectType) []*fetchedInstance {
+	if instance == nil || instance.children[brokerObjectType] == nil {
+		return nil
+	}
+	var result []*fetchedInstance
+	for _, child := range instance.children[brokerObjectType].instances {
+		if !child.systemProvisioned && !child.filtered {
+			result = append(result, child)
+		}
+	}
+	return result
+}
+
+// indexInstances maps the names of instances to the instances, so that the objects of two sources can be matched
+func indexInstances(instances []*fetchedInstance) map[string]*fetchedInstance {
+	index := make(map[string]*fetchedInstance, len(instances))
+	for _, instance := range instances {
+		index[instance.name] = instance
+	}
+	return index
+}
+
+// diffAttributes compares the non-identifying attributes of an object in the order of the resource schema
+func diffAttributes(brokerObjectType BrokerObjectType, from *fetchedInstance, to *fetchedInstance) ([]AttributeDiff, error) {
+	fromValues, err := comparableAttributeValues(brokerObjectType, from)
+	if err != nil {
+		return nil, err
+	}
+	toValues, err := comparableAttributeValues(brokerObjectType, to)
+	if err != nil {
+		return nil, err
+	}
+	var result []AttributeDiff
+	for _, attr := range internalbroker.Entities[dsLookup[brokerObjectType]].Attributes {
+		fromValue, inFrom := fromValues[attr.TerraformName]
+		toValue, inTo := toValues[attr.TerraformName]
+		if inFrom == inTo && fromValue == toValue {
+			continue
+		}
+		attributeDiff := AttributeDiff{Name: attr.TerraformName}
+		if inFrom {
+			attributeDiff.From = &fromValue
+		}
+		if inTo {
+			attributeDiff.To = &toValue
+		}
+		result = append(result, attributeDiff)
+	}
+	return result, nil
+}
+
+// comparableAttributeValues returns the non-identifying attribute values of an object as generated
+func comparableAttributeValues(brokerObjectType BrokerObjectType, instance *fetchedInstance) (map[string]string, error) {
+	attributes := internalbroker.Entities[dsLookup[brokerObjectType]].Attributes
+	resourceValues, variables, err := processSempResults("solacebroker_"+string(brokerObjectType)+" object", attributes, []map[string]any{instance.result}, BrokerObjectInstanceInfo{})
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, attr := range attributes {
+		info, ok := resourceValues[0].ResourceAttributes[attr.TerraformName]
+		if !ok || attr.Identifying {
+			continue
+		}
+		value := info.AttributeValue
+		// Attributes linked to write-only attributes are generated as variables, compare their values instead
+		if variable, ok := variables[strings.TrimPrefix(value, "var.")]; ok && strings.HasPrefix(value, "var.") {
+			value = variable.Default
+		}
+		values[attr.TerraformName] = value
+	}
+	return values, nil
+}
+
+// WriteDiff writes a diff as human-readable text or, if the output format is "json", as JSON
+func WriteDiff(writer io.Writer, diff *Diff, outputFormat string) error {
+	if outputFormat == "json" {
+		data, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(writer, string(data))
+		return err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", diff.From, diff.To)
+	counts := map[string]int{}
+	for _, objectDiff := range diff.Objects {
+		counts[objectDiff.Change]++
+		symbol := map[string]string{DiffAdded: "+", DiffRemoved: "-", DiffChanged: "~"}[objectDiff.Change]
+		fmt.Fprintf(&sb, "%s %s %s\n", symbol, objectDiff.ObjectType, objectDiff.Identifier)
+		for _, attributeDiff := range objectDiff.Attributes {
+			fmt.Fprintf(&sb, "    %s: %s -> %s\n", attributeDiff.Name, diffValueText(attributeDiff.From), diffValueText(attributeDiff.To))
+		}
+	}
+	if len(diff.Objects) == 0 {
+		sb.WriteString("No differences.\n")
+	} else {
+		fmt.Fprintf(&sb, "%d added, %d removed, %d changed.\n", counts[DiffAdded], counts[DiffRemoved], counts[DiffChanged])
+	}
+	_, err := io.WriteString(writer, sb.String())
+	return err
+}
+
+func diffValueText(value *string) string {
+	if value == nil {
+		return "(default)"
+	}
+	return *value
+}
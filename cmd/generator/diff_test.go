@@ -0,0 +1,136 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"terraform-provider-solacebroker/internal/broker"
+	"terraform-provider-solacebroker/internal/broker/sempmock"
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func newDiffTestBroker(t *testing.T, objects map[string]map[string]any) *semp.Client {
+	mockBroker := sempmock.New()
+	t.Cleanup(mockBroker.Close)
+	for path, data := range objects {
+		if err := mockBroker.SetObject(path, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return semp.NewClient(mockBroker.URL+broker.SempDetail.BasePath, false, false, semp.Retries(0, 0, 0), semp.RequestLimits(semp.DefaultRequestTimeout, 0), semp.BasicAuth("admin", "admin"))
+}
+
+func newDiffTestCliParams(excludeTypes ...string) CliParams {
+	var none []string
+	var parallelism int64 = 1
+	module, moduleVariables := false, ""
+	return CliParams{Include_type: &none, Exclude_type: &excludeTypes, Include_name: &none, Exclude_name: &none, Parallelism: &parallelism, Module: &module, Module_variables: &moduleVariables}
+}
+
+func TestDiffBrokerConfigs(t *testing.T) {
+	createBrokerObjectRelationships()
+	staging := newDiffTestBroker(t, map[string]map[string]any{
+		"/msgVpns/staging":                           {"enabled": true},
+		"/msgVpns/staging/queues/q1":                 {"maxBindCount": 10, "owner": "me"},
+		"/msgVpns/staging/queues/q2":                 {},
+		"/msgVpns/staging/queues/q2/subscriptions/a": {},
+		"/msgVpns/staging/aclProfiles/acl":           {},
+	})
+	production := newDiffTestBroker(t, map[string]map[string]any{
+		"/msgVpns/prod":                  {"enabled": true},
+		"/msgVpns/prod/queues/q1":        {"maxBindCount": 20, "owner": "me"},
+		"/msgVpns/prod/queues/q3":        {},
+		"/msgVpns/prod/aclProfiles/acl":  {},
+		"/msgVpns/prod/aclProfiles/acl2": {},
+	})
+	from, err := DiffSourceFromBroker(newDiffTestCliParams(), context.Background(), staging, "msg_vpn", "staging", "staging")
+	if err != nil {
+		t.Fatalf("DiffSourceFromBroker() error = %v", err)
+	}
+	to, err := DiffSourceFromBroker(newDiffTestCliParams(), context.Background(), production, "msg_vpn", "prod", "production")
+	if err != nil {
+		t.Fatalf("DiffSourceFromBroker() error = %v", err)
+	}
+	diff, err := DiffBrokerConfigs(from, to)
+	if err != nil {
+		t.Fatalf("DiffBrokerConfigs() error = %v", err)
+	}
+	maxBindCount10, maxBindCount20 := "10", "20"
+	want := []ObjectDiff{
+		{Change: DiffAdded, ObjectType: "solacebroker_msg_vpn_acl_profile", Identifier: "prod/acl2"},
+		{Change: DiffChanged, ObjectType: "solacebroker_msg_vpn_queue", Identifier: "staging/q1", Attributes: []AttributeDiff{{Name: "max_bind_count", From: &maxBindCount10, To: &maxBindCount20}}},
+		{Change: DiffRemoved, ObjectType: "solacebroker_msg_vpn_queue", Identifier: "staging/q2"},
+		{Change: DiffRemoved, ObjectType: "solacebroker_msg_vpn_queue_subscription", Identifier: "staging/q2/a"},
+		{Change: DiffAdded, ObjectType: "solacebroker_msg_vpn_queue", Identifier: "prod/q3"},
+	}
+	if diff.From != "staging" || diff.To != "production" || !reflect.DeepEqual(diff.Objects, want) {
+		data, _ := json.MarshalIndent(diff, "", "  ")
+		t.Errorf("DiffBrokerConfigs() = %s", data)
+	}
+
+	var text bytes.Buffer
+	if err := WriteDiff(&text, diff, "text"); err != nil {
+		t.Fatalf("WriteDiff() error = %v", err)
+	}
+	wantText := `--- staging
++++ production
++ solacebroker_msg_vpn_acl_profile prod/acl2
+~ solacebroker_msg_vpn_queue staging/q1
+    max_bind_count: 10 -> 20
+- solacebroker_msg_vpn_queue staging/q2
+- solacebroker_msg_vpn_queue_subscription staging/q2/a
++ solacebroker_msg_vpn_queue prod/q3
+2 added, 2 removed, 1 changed.
+`
+	if text.String() != wantText {
+		t.Errorf("WriteDiff() text = %s, want %s", text.String(), wantText)
+	}
+	var jsonOutput bytes.Buffer
+	if err := WriteDiff(&jsonOutput, diff, "json"); err != nil {
+		t.Fatalf("WriteDiff() error = %v", err)
+	}
+	var readDiff Diff
+	if err := json.Unmarshal(jsonOutput.Bytes(), &readDiff); err != nil || !reflect.DeepEqual(&readDiff, diff) {
+		t.Errorf("WriteDiff() json = %s, error = %v", jsonOutput.String(), err)
+	}
+
+	// The same broker, as snapshot, does not differ; the filtered differences are ignored
+	snapshot, err := TakeSnapshot(context.Background(), staging, "msg_vpn", "staging", nil, 1, nil)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error = %v", err)
+	}
+	from, err = DiffSourceFromSnapshot(newDiffTestCliParams(), snapshot, "snapshot")
+	if err != nil {
+		t.Fatalf("DiffSourceFromSnapshot() error = %v", err)
+	}
+	to, err = DiffSourceFromBroker(newDiffTestCliParams(), context.Background(), staging, "msg_vpn", "staging", "staging")
+	if err != nil {
+		t.Fatalf("DiffSourceFromBroker() error = %v", err)
+	}
+	if diff, err := DiffBrokerConfigs(from, to); err != nil || len(diff.Objects) != 0 {
+		t.Errorf("DiffBrokerConfigs() of snapshot and broker = %v, error = %v, want no differences", diff, err)
+	}
+	text.Reset()
+	if err := WriteDiff(&text, &Diff{From: "a", To: "b"}, "text"); err != nil || text.String() != "--- a\n+++ b\nNo differences.\n" {
+		t.Errorf("WriteDiff() text = %q, error = %v", text.String(), err)
+	}
+}
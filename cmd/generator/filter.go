@@ -0,0 +1,170 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"regexp"
+	"strings"
+
+	"terraform-provider-solacebroker/internal/broker"
+)
+
+// Filters restrict the broker objects the generator walks. Excluding an object or type prunes its whole subtree.
+type Filters struct {
+	includeTypes []*regexp.Regexp
+	excludeTypes []*regexp.Regexp
+	includeNames []nameFilter
+	excludeNames []nameFilter
+}
+
+// nameFilter matches the names of objects of one type, or of all types if objectType is empty
+type nameFilter struct {
+	objectType *regexp.Regexp
+	pattern    *regexp.Regexp
+}
+
+// NewFilters parses the type and name filters. Type filters are patterns on the resource type, with or without the
+// "solacebroker_" prefix. Name filters are patterns on the identifying attributes of an object, optionally restricted
+// to a type in the form <type pattern>=<name pattern>. Patterns are globs, or regular expressions if prefixed by "re:".
+func NewFilters(includeTypes []string, excludeTypes []string, includeNames []string, excludeNames []string) (*Filters, error) {
+	// The parent-child relationship between broker objects is required to match the parent and child types
+	createBrokerObjectRelationships()
+	filters := &Filters{}
+	var err error
+	if filters.includeTypes, err = parseTypePatterns(includeTypes); err != nil {
+		return nil, err
+	}
+	if filters.excludeTypes, err = parseTypePatterns(excludeTypes); err != nil {
+		return nil, err
+	}
+	if filters.includeNames, err = parseNameFilters(includeNames); err != nil {
+		return nil, err
+	}
+	if filters.excludeNames, err = parseNameFilters(excludeNames); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+func parseTypePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	var result []*regexp.Regexp
+	for _, pattern := range patterns {
+		rex, err := broker.CompilePattern(strings.TrimPrefix(pattern, "solacebroker_"))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, rex)
+	}
+	return result, nil
+}
+
+func parseNameFilters(patterns []string) ([]nameFilter, error) {
+	var result []nameFilter
+	for _, pattern := range patterns {
+		filter := nameFilter{}
+		// A type can be specified unless the name pattern is a regular expression, which may contain "="
+		if objectType, namePattern, found := strings.Cut(pattern, "="); found && !strings.HasPrefix(pattern, broker.RegexPatternPrefix) {
+			rex, err := broker.CompilePattern(strings.TrimPrefix(objectType, "solacebroker_"))
+			if err != nil {
+				return nil, err
+			}
+			filter.objectType = rex
+			pattern = namePattern
+		}
+		rex, err := broker.CompilePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		filter.pattern = rex
+		result = append(result, filter)
+	}
+	return result, nil
+}
+
+func matchesAny(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SkipType returns true if no objects of the type are to be generated. If include types are set, a type is only
+// walked if it, one of its parent types or one of its child types is included, the latter to reach the child types.
+func (f *Filters) SkipType(brokerObjectType BrokerObjectType) bool {
+	if f == nil {
+		return false
+	}
+	if matchesAny(f.excludeTypes, string(brokerObjectType)) {
+		return true
+	}
+	if len(f.includeTypes) == 0 {
+		return false
+	}
+	return !f.includesTypeOrChildType(brokerObjectType) && !f.includesParentType(brokerObjectType)
+}
+
+func (f *Filters) includesTypeOrChildType(brokerObjectType BrokerObjectType) bool {
+	if matchesAny(f.includeTypes, string(brokerObjectType)) {
+		return true
+	}
+	for _, childType := range brokerObjectRelationship[brokerObjectType] {
+		if f.includesTypeOrChildType(childType) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filters) includesParentType(brokerObjectType BrokerObjectType) bool {
+	for parentType, childTypes := range brokerObjectRelationship {
+		for _, childType := range childTypes {
+			if childType == brokerObjectType && (matchesAny(f.includeTypes, string(parentType)) || f.includesParentType(parentType)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SkipObject returns true if an object is not to be generated, based on its own identifying attribute values
+func (f *Filters) SkipObject(brokerObjectType BrokerObjectType, name string) bool {
+	if f == nil {
+		return false
+	}
+	if matchesNameFilters(f.excludeNames, brokerObjectType, name) {
+		return true
+	}
+	// Only objects of types that have include name filters are restricted by them
+	restricted := false
+	for _, filter := range f.includeNames {
+		if filter.objectType == nil || filter.objectType.MatchString(string(brokerObjectType)) {
+			restricted = true
+			break
+		}
+	}
+	return restricted && !matchesNameFilters(f.includeNames, brokerObjectType, name)
+}
+
+func matchesNameFilters(filters []nameFilter, brokerObjectType BrokerObjectType, name string) bool {
+	for _, filter := range filters {
+		if (filter.objectType == nil || filter.objectType.MatchString(string(brokerObjectType))) && filter.pattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+)
+
+func TestFiltersSkipType(t *testing.T) {
+	createBrokerObjectRelationships()
+	tests := []struct {
+		name         string
+		includeTypes []string
+		excludeTypes []string
+		objectType   BrokerObjectType
+		want         bool
+	}{
+		{"NoFilters", nil, nil, "msg_vpn_queue", false},
+		{"Excluded", nil, []string{"msg_vpn_mqtt_*"}, "msg_vpn_mqtt_session", true},
+		{"ExcludedWithPrefix", nil, []string{"solacebroker_msg_vpn_queue"}, "msg_vpn_queue", true},
+		{"NotExcluded", nil, []string{"msg_vpn_mqtt_*"}, "msg_vpn_queue", false},
+		{"Included", []string{"msg_vpn_queue"}, nil, "msg_vpn_queue", false},
+		{"ChildOfIncluded", []string{"msg_vpn_queue"}, nil, "msg_vpn_queue_subscription", false},
+		{"ParentOfIncluded", []string{"msg_vpn_queue_subscription"}, nil, "msg_vpn_queue", false},
+		{"NotIncluded", []string{"msg_vpn_queue"}, nil, "msg_vpn_acl_profile", true},
+		{"IncludedAndExcluded", []string{"msg_vpn_queue"}, []string{"msg_vpn_queue_subscription"}, "msg_vpn_queue_subscription", true},
+		{"IncludedRegex", []string{"re:msg_vpn_(queue|topic_endpoint)"}, nil, "msg_vpn_topic_endpoint", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := NewFilters(tt.includeTypes, tt.excludeTypes, nil, nil)
+			if err != nil {
+				t.Fatalf("NewFilters() error = %v", err)
+			}
+			if got := filters.SkipType(tt.objectType); got != tt.want {
+				t.Errorf("SkipType(%v) = %v, want %v", tt.objectType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFiltersSkipObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeNames []string
+		excludeNames []string
+		objectType   BrokerObjectType
+		objectName   string
+		want         bool
+	}{
+		{"NoFilters", nil, nil, "msg_vpn_queue", "q", false},
+		{"Included", []string{"msg_vpn_queue=orders/*"}, nil, "msg_vpn_queue", "orders/new/eu", false},
+		{"NotIncluded", []string{"msg_vpn_queue=orders/*"}, nil, "msg_vpn_queue", "invoices/new", true},
+		{"OtherTypeNotRestricted", []string{"msg_vpn_queue=orders/*"}, nil, "msg_vpn_acl_profile", "default", false},
+		{"IncludedAllTypes", []string{"orders?"}, nil, "msg_vpn_acl_profile", "orders1", false},
+		{"NotIncludedAllTypes", []string{"orders?"}, nil, "msg_vpn_acl_profile", "orders12", true},
+		{"Excluded", nil, []string{"msg_vpn_queue=re:tmp-[0-9]+"}, "msg_vpn_queue", "tmp-12", true},
+		{"NotExcluded", nil, []string{"msg_vpn_queue=re:tmp-[0-9]+"}, "msg_vpn_queue", "tmp-x", false},
+		{"IncludedAndExcluded", []string{"msg_vpn_queue=orders/*"}, []string{"*/test"}, "msg_vpn_queue", "orders/test", true},
+		{"RegexWithEquals", nil, []string{"re:a=b"}, "msg_vpn_queue", "a=b", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := NewFilters(nil, nil, tt.includeNames, tt.excludeNames)
+			if err != nil {
+				t.Fatalf("NewFilters() error = %v", err)
+			}
+			if got := filters.SkipObject(tt.objectType, tt.objectName); got != tt.want {
+				t.Errorf("SkipObject(%v, %v) = %v, want %v", tt.objectType, tt.objectName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewFiltersInvalid(t *testing.T) {
+	tests := []struct {
+		name         string
+		includeTypes []string
+		excludeNames []string
+	}{
+		{"EmptyType", []string{""}, nil},
+		{"InvalidRegex", nil, []string{"msg_vpn_queue=re:("}},
+		{"EmptyName", nil, []string{"msg_vpn_queue="}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFilters(tt.includeTypes, nil, nil, tt.excludeNames); err == nil {
+				t.Errorf("NewFilters() did not return an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+// SempClient fetches the broker configuration for the generator. The SEMP client of the provider implements it.
+type SempClient interface {
+	// RequestWithoutBodyForGenerator returns the objects at the SEMP URL relative to the base path, following the
+	// paging of collections, appended to appendToResult. Errors for paths that the broker doesn't support must match
+	// ErrInvalidPath with errors.Is.
+	RequestWithoutBodyForGenerator(ctx context.Context, basePath string, method string, url string, appendToResult []map[string]any) ([]map[string]any, error)
+}
+
+// ErrInvalidPath is the error of a SempClient for a path that the broker doesn't support, for example of an object
+// type of a newer broker version. The generator skips such object types.
+var ErrInvalidPath = semp.ErrInvalidPath
+
+// Config configures the generation of the Terraform configuration of a broker object and its child objects
+type Config struct {
+	ResourceType    string           // the resource type of the object, for example "solacebroker_msg_vpn"; the prefix is optional
+	ResourceName    string           // the name of the object's resource in the generated configuration
+	Identifier      string           // the provider-specific identifier of the object, not used for snapshots
+	IncludeTypes    []string         // type patterns of the child objects to generate, see NewFilters
+	ExcludeTypes    []string         // type patterns of the child objects to skip with their children
+	IncludeNames    []string         // [<type pattern>=]<name pattern> of the child objects to generate
+	ExcludeNames    []string         // [<type pattern>=]<name pattern> of the child objects to skip with their children
+	Parallelism     int              // the maximum number of concurrent SEMP requests, 1 if not set
+	Module          bool             // generate a reusable module with the identifiers of the object as input variables
+	ModuleVariables []ModuleVariable // further values to parameterize in a module
+	Log             io.Writer        // receives progress messages and warnings, discarded if not set
+}
+
+// WriteOptions control how a generated configuration is written
+type WriteOptions struct {
+	OutputFormat string // "json" for the Terraform JSON configuration syntax, otherwise HCL
+	ImportBlocks bool   // also write an import block for each resource, not supported for modules
+	// The authentication of the provider configuration, which determines the provider variables
+	BasicAuthentication             bool
+	BearerTokenAuthentication       bool
+	OAuthAuthentication             bool
+	ClientCertificateAuthentication bool
+}
+
+// Result is the generated configuration of a broker object and its child objects
+type Result struct {
+	Resources      []map[string]ResourceConfig // indexed by resource type and name, in the order the objects were found
+	Variables      map[string]VariableConfig   // indexed by variable name
+	Outputs        map[string]string           // output values of a module, indexed by output name
+	Module         bool
+	SkippedTypes   []string // resource types skipped by a type filter
+	SkippedObjects []string // objects skipped by a name filter, with their type
+	Warnings       []string // issues the configuration may need to be reviewed for
+}
+
+// Generate generates the configuration of a broker object and its child objects from the broker. Progress messages
+// and warnings are also logged to the Log of the config.
+func Generate(ctx context.Context, client SempClient, config Config) (*Result, error) {
+	brokerObjectType, options, err := config.generateOptions()
+	if err != nil {
+		return nil, err
+	}
+	if config.Identifier == "" {
+		return nil, errors.New("the identifier of the object is required")
+	}
+	fetchedConfig, err := fetchBrokerConfig(ctx, client, brokerObjectType, config.ResourceName, config.Identifier, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch broker config: %w", err)
+	}
+	return newResult(fetchedConfig, brokerObjectType, config), nil
+}
+
+// GenerateFromSnapshot generates the configuration of the object of a snapshot and its child objects, as Generate
+// does from the broker. The resource type of the config may be omitted.
+func GenerateFromSnapshot(snapshot *Snapshot, config Config) (*Result, error) {
+	if config.ResourceType == "" {
+		config.ResourceType = snapshot.ObjectType
+	}
+	brokerObjectType, options, err := config.generateOptions()
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.ObjectType != string(brokerObjectType) {
+		return nil, fmt.Errorf("the snapshot contains a %s object, not %s", "solacebroker_"+snapshot.ObjectType, "solacebroker_"+brokerObjectType)
+	}
+	snapshotConfig, err := snapshotBrokerConfig(snapshot, config.ResourceName, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read broker config from snapshot: %w", err)
+	}
+	return newResult(snapshotConfig, brokerObjectType, config), nil
+}
+
+// generateOptions verifies the config for generating, returning the broker object type and the options to fetch it with
+func (config Config) generateOptions() (BrokerObjectType, crawlOptions, error) {
+	if !IsValidTerraformIdentifier(config.ResourceName) {
+		return "", crawlOptions{}, fmt.Errorf("resource name %q is not a valid Terraform identifier", config.ResourceName)
+	}
+	return config.crawlOptions()
+}
+
+// crawlOptions verifies the resource type and filters of the config, returning the broker object type and the options
+// to fetch it with
+func (config Config) crawlOptions() (BrokerObjectType, crawlOptions, error) {
+	brokerObjectType := BrokerObjectType(strings.TrimPrefix(config.ResourceType, "solacebroker_"))
+	if !IsBrokerObjectType(brokerObjectType) {
+		return "", crawlOptions{}, fmt.Errorf("broker resource not found by terraform name: %s", config.ResourceType)
+	}
+	filters, err := NewFilters(config.IncludeTypes, config.ExcludeTypes, config.IncludeNames, config.ExcludeNames)
+	if err != nil {
+		return "", crawlOptions{}, fmt.Errorf("invalid filter: %w", err)
+	}
+	return brokerObjectType, crawlOptions{filters: filters, parallelism: config.Parallelism, log: config.Log}, nil
+}
+
+// newResult post-processes the fetched config into the result
+func newResult(config *brokerConfig, brokerObjectType BrokerObjectType, generatorConfig Config) *Result {
+	result := &Result{
+		Resources: config.resources,
+		Variables: config.variables,
+		Module:    generatorConfig.Module,
+	}
+	for _, filteredType := range config.filteredTypes {
+		result.SkippedTypes = append(result.SkippedTypes, string(filteredType))
+	}
+	result.SkippedObjects = config.filteredObjects
+
+	// Postprocess brokerResources for dependencies
+	logInfo(config.log, "Replacing hardcoded names of inter-object dependencies by references where required")
+	addInterObjectReferences(config)
+
+	if generatorConfig.Module {
+		logInfo(config.log, "Replacing environment specific values by module variables")
+		moduleInputs, moduleOutputs, unused := parameterizeForModule(config.resources, brokerObjectType, generatorConfig.ModuleVariables)
+		for key, value := range moduleInputs {
+			result.Variables[key] = value
+		}
+		result.Outputs = moduleOutputs
+		for _, name := range unused {
+			config.warn(fmt.Sprintf("Value of module variable %s not found in the configuration", name))
+		}
+	}
+	result.Warnings = config.warnings
+	return result
+}
+
+// Write writes the configuration to a Terraform file, or to a directory for a module or if the file name ends with
+// "/" or is an existing directory
+func (r *Result) Write(fileName string, options WriteOptions) error {
+	if options.OutputFormat != "" && options.OutputFormat != "hcl" && options.OutputFormat != "json" {
+		return fmt.Errorf("unsupported output format %s, must be hcl or json", options.OutputFormat)
+	}
+	if r.Module && options.ImportBlocks {
+		return errors.New("cannot generate import blocks for a module, Terraform only supports them in the root module")
+	}
+	brokerResources, err := resourcesToFormattedHCL(r.Resources)
+	if err != nil {
+		return fmt.Errorf("failed to render resources: %w", err)
+	}
+	object := &ObjectInfo{
+		BasicAuthentication:             options.BasicAuthentication,
+		BearerTokenAuthentication:       options.BearerTokenAuthentication,
+		OAuthAuthentication:             options.OAuthAuthentication,
+		ClientCertificateAuthentication: options.ClientCertificateAuthentication,
+		FileName:                        fileName,
+		ImportBlocks:                    options.ImportBlocks,
+		Module:                          r.Module,
+		OutputFormat:                    options.OutputFormat,
+		BrokerResources:                 brokerResources,
+		Resources:                       r.Resources,
+		ImportIds:                       resourcesToImportIds(r.Resources),
+		Outputs:                         r.Outputs,
+		Variables:                       r.Variables,
+	}
+	if object.Module || IsDirectoryOutput(fileName) {
+		return GenerateTerraformDirectory(object)
+	}
+	return GenerateTerraformFile(object)
+}
+
+// ConfigFromCliParams returns the config of the generator params, which must have been complemented with
+// UpdateCliParamsWithEnv or UpdateOfflineCliParamsWithEnv. The Log of the config is not set.
+func ConfigFromCliParams(cliParams CliParams, resourceType string, resourceName string, identifier string) (Config, error) {
+	moduleVariables, err := ParseModuleVariables(*cliParams.Module_variables)
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid module variables: %w", err)
+	}
+	return Config{
+		ResourceType:    resourceType,
+		ResourceName:    resourceName,
+		Identifier:      identifier,
+		IncludeTypes:    *cliParams.Include_type,
+		ExcludeTypes:    *cliParams.Exclude_type,
+		IncludeNames:    *cliParams.Include_name,
+		ExcludeNames:    *cliParams.Exclude_name,
+		Parallelism:     int(*cliParams.Parallelism),
+		Module:          *cliParams.Module,
+		ModuleVariables: moduleVariables,
+	}, nil
+}
+
+// WriteOptionsFromCliParams returns the write options of the generator params, with the provider authentication of
+// the connection params
+func WriteOptionsFromCliParams(cliParams CliParams) WriteOptions {
+	return WriteOptions{
+		OutputFormat:                    *cliParams.Output_format,
+		ImportBlocks:                    *cliParams.Import_blocks,
+		BasicAuthentication:             *cliParams.Bearer_token == "" && *cliParams.Oauth_token_url == "" && (*cliParams.Username != "" || !cliParams.HasClientCertificate()),
+		BearerTokenAuthentication:       *cliParams.Bearer_token != "",
+		OAuthAuthentication:             *cliParams.Oauth_token_url != "",
+		ClientCertificateAuthentication: cliParams.HasClientCertificate(),
+	}
+}
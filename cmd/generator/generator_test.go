@@ -0,0 +1,168 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	client := newDiffTestBroker(t, map[string]map[string]any{
+		"/msgVpns/test":           {"enabled": true},
+		"/msgVpns/test/queues/q1": {"maxBindCount": 10},
+	})
+	result, err := Generate(context.Background(), client, Config{
+		ResourceType:    "solacebroker_msg_vpn",
+		ResourceName:    "test",
+		Identifier:      "test",
+		Parallelism:     1,
+		Module:          true,
+		ModuleVariables: []ModuleVariable{{Name: "unused", Value: "not-found"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, resources := range result.Resources {
+		for name := range resources {
+			found[name] = true
+		}
+	}
+	for _, name := range []string{"solacebroker_msg_vpn test", "solacebroker_msg_vpn_queue test_q1"} {
+		if !found[name] {
+			t.Errorf("Generate() resources %v, missing %s", found, name)
+		}
+	}
+	if _, ok := result.Variables["msg_vpn_name"]; !ok {
+		t.Errorf("Generate() variables %v, missing msg_vpn_name", result.Variables)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "unused") {
+		t.Errorf("Generate() warnings %v, expected the unused module variable", result.Warnings)
+	}
+
+	dir := t.TempDir()
+	if err := result.Write(dir, WriteOptions{BasicAuthentication: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "variables.tf")); err != nil {
+		t.Errorf("Write() did not write the module variables: %v", err)
+	}
+	if err := result.Write(dir, WriteOptions{ImportBlocks: true}); err == nil {
+		t.Errorf("Write() import blocks for a module, expected error")
+	}
+	if err := result.Write(dir, WriteOptions{OutputFormat: "yaml"}); err == nil {
+		t.Errorf("Write() unsupported output format, expected error")
+	}
+}
+
+func TestGenerateLog(t *testing.T) {
+	client := newDiffTestBroker(t, map[string]map[string]any{
+		"/msgVpns/test":           {},
+		"/msgVpns/test/queues/q1": {},
+	})
+	defaultLog := LogWriter
+	t.Cleanup(func() { LogWriter = defaultLog })
+	var cliLog bytes.Buffer
+	LogWriter = &cliLog
+	// concurrent calls only log to their own config
+	logs := make([]bytes.Buffer, 3)
+	var wg sync.WaitGroup
+	for i := range logs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			config := Config{ResourceType: "solacebroker_msg_vpn", ResourceName: "test", Identifier: "test"}
+			if i > 0 {
+				config.Log = &logs[i]
+			}
+			if _, err := Generate(context.Background(), client, config); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if cliLog.Len() != 0 {
+		t.Errorf("Generate() without log logged %q", cliLog.String())
+	}
+	for _, log := range logs[1:] {
+		if !strings.Contains(log.String(), "Fetching config for resource msg_vpn_queue") {
+			t.Errorf("Generate() log = %q, missing the progress messages", log.String())
+		}
+	}
+}
+
+// stubSempClient serves the objects of a few paths, other paths are invalid
+type stubSempClient map[string][]map[string]any
+
+func (c stubSempClient) RequestWithoutBodyForGenerator(_ context.Context, _ string, _ string, url string, appendToResult []map[string]any) ([]map[string]any, error) {
+	objects, ok := c[strings.SplitN(url, "?", 2)[0]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPath, url)
+	}
+	return append(appendToResult, objects...), nil
+}
+
+func TestGenerateSempClient(t *testing.T) {
+	client := stubSempClient{
+		"/msgVpns/test":        {{"msgVpnName": "test"}},
+		"/msgVpns/test/queues": {{"msgVpnName": "test", "queueName": "q1"}},
+	}
+	result, err := Generate(context.Background(), client, Config{ResourceType: "solacebroker_msg_vpn", ResourceName: "test", Identifier: "test"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(result.Resources) != 2 {
+		t.Errorf("Generate() resources = %v, want the message VPN and the queue", result.Resources)
+	}
+	if len(result.Warnings) == 0 {
+		t.Errorf("Generate() no warnings for the invalid paths")
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	client := newDiffTestBroker(t, map[string]map[string]any{"/msgVpns/test": {}})
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"unknown type", Config{ResourceType: "solacebroker_unknown", ResourceName: "test", Identifier: "test"}},
+		{"invalid name", Config{ResourceType: "solacebroker_msg_vpn", ResourceName: "1test", Identifier: "test"}},
+		{"invalid filter", Config{ResourceType: "solacebroker_msg_vpn", ResourceName: "test", Identifier: "test", IncludeNames: []string{"re:["}}},
+		{"missing identifier", Config{ResourceType: "solacebroker_msg_vpn", ResourceName: "test"}},
+		{"object not found", Config{ResourceType: "solacebroker_msg_vpn", ResourceName: "test", Identifier: "other"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(context.Background(), client, tt.config); err == nil {
+				t.Errorf("Generate() expected error")
+			}
+		})
+	}
+}
+
+func TestGenerateFromSnapshotTypeMismatch(t *testing.T) {
+	snapshot := &Snapshot{ObjectType: "msg_vpn"}
+	if _, err := GenerateFromSnapshot(snapshot, Config{ResourceType: "solacebroker_msg_vpn_queue", ResourceName: "test"}); err == nil {
+		t.Errorf("GenerateFromSnapshot() expected error")
+	}
+}
@@ -0,0 +1,172 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-solacebroker/internal/broker"
+	"unicode"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// hclStringValue returns the HCL expression of a string: a heredoc for multi-line content such as certificates,
+// otherwise a quoted string
+func hclStringValue(value string) string {
+	if strings.Count(value, "\n") > 1 && strings.HasSuffix(value, "\n") && strings.IndexFunc(value, isNonPrintableInHeredoc) < 0 {
+		delimiter := "EOT"
+		for containsLine(value, delimiter) {
+			delimiter += "_"
+		}
+		return "<<" + delimiter + "\n" + escapeHclTemplate(value) + delimiter
+	}
+	return string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes())
+}
+
+func isNonPrintableInHeredoc(r rune) bool {
+	return r != '\n' && r != '\t' && !unicode.IsPrint(r)
+}
+
+func containsLine(value string, line string) bool {
+	for _, l := range strings.Split(value, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeHclTemplate escapes the template sequences of HCL strings
+func escapeHclTemplate(value string) string {
+	value = strings.ReplaceAll(value, "${", "$${")
+	return strings.ReplaceAll(value, "%{", "%%{")
+}
+
+// hclStringLiteral returns the string of a quoted HCL string without template interpolations, unescaped, or false if
+// the expression is not one
+func hclStringLiteral(expression string) (string, bool) {
+	if !strings.HasPrefix(expression, "\"") {
+		return "", false
+	}
+	expr, diags := hclsyntax.ParseExpression([]byte(expression), "", hcl.InitialPos)
+	template, ok := expr.(*hclsyntax.TemplateExpr)
+	if diags.HasErrors() || !ok || !template.IsStringLiteral() {
+		return "", false
+	}
+	value, diags := template.Value(nil)
+	if diags.HasErrors() || !value.Type().Equals(cty.String) {
+		return "", false
+	}
+	return value.AsString(), true
+}
+
+// hclNumberValue returns the HCL expression of a number as returned by SEMP, without exponent
+func hclNumberValue(value any) string {
+	if number, ok := value.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// hclObjectValue returns the HCL expression of a nested object with the attributes in the order of the schema
+func hclObjectValue(attributes []*broker.AttributeInfo, value map[string]any) string {
+	return string(hclObjectTokens(attributes, value).Bytes())
+}
+
+func hclObjectTokens(attributes []*broker.AttributeInfo, value map[string]any) hclwrite.Tokens {
+	var objectAttributes []hclwrite.ObjectAttrTokens
+	for _, attr := range attributes {
+		attributeValue := value[attr.SempName]
+		if attributeValue == nil {
+			continue
+		}
+		var tokens hclwrite.Tokens
+		switch attr.BaseType {
+		case broker.String:
+			tokens = hclwrite.TokensForValue(cty.StringVal(fmt.Sprint(attributeValue)))
+		case broker.Int64:
+			tokens = hclwrite.Tokens{{Type: hclsyntax.TokenNumberLit, Bytes: []byte(hclNumberValue(attributeValue))}}
+		case broker.Bool:
+			boolValue, _ := attributeValue.(bool)
+			tokens = hclwrite.TokensForValue(cty.BoolVal(boolValue))
+		case broker.Struct:
+			nestedValue, _ := attributeValue.(map[string]any)
+			tokens = hclObjectTokens(attr.Attributes, nestedValue)
+		default:
+			continue
+		}
+		objectAttributes = append(objectAttributes, hclwrite.ObjectAttrTokens{
+			Name:  hclwrite.TokensForIdentifier(attr.TerraformName),
+			Value: tokens,
+		})
+	}
+	return hclwrite.TokensForObject(objectAttributes)
+}
+
+// hclExpressionTokens parses an HCL expression, failing if it is not a single valid expression
+func hclExpressionTokens(expression string) (hclwrite.Tokens, error) {
+	file, diags := hclwrite.ParseConfig([]byte("value = "+expression+"\n"), "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	attribute := file.Body().GetAttribute("value")
+	if attribute == nil || len(file.Body().Attributes()) != 1 || len(file.Body().Blocks()) != 0 {
+		return nil, fmt.Errorf("%q is not a single expression", expression)
+	}
+	return attribute.Expr().BuildTokens(nil), nil
+}
+
+// hclFormatResource renders a resource block with its attributes sorted by name and their comments
+func hclFormatResource(resourceTypeAndName string, resourceConfig ResourceConfig) (string, error) {
+	var attributeNames []string
+	for attributeName := range resourceConfig.ResourceAttributes {
+		attributeNames = append(attributeNames, attributeName)
+	}
+	sort.Strings(attributeNames)
+	file := hclwrite.NewEmptyFile()
+	body := file.Body().AppendNewBlock("resource", strings.SplitN(resourceTypeAndName, " ", 2)).Body()
+	for _, attributeName := range attributeNames {
+		info := resourceConfig.ResourceAttributes[attributeName]
+		tokens, err := hclExpressionTokens(info.AttributeValue)
+		if err != nil {
+			return "", fmt.Errorf("invalid value of %s attribute %s: %w", resourceTypeAndName, attributeName, err)
+		}
+		if comment := strings.TrimSpace(info.Comment); comment != "" {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComment, Bytes: []byte(comment)})
+		}
+		body.SetAttributeRaw(attributeName, tokens)
+	}
+	return strings.TrimSuffix(string(hclwrite.Format(file.Bytes())), "\n"), nil
+}
+
+// formatHcl verifies that generated configuration parses and formats it in the canonical style
+func formatHcl(fileName string, content []byte) ([]byte, error) {
+	if _, diags := hclsyntax.ParseConfig(content, fileName, hcl.InitialPos); diags.HasErrors() {
+		return nil, fmt.Errorf("generated configuration is invalid: %w", diags)
+	}
+	return hclwrite.Format(content), nil
+}
+
+// commentLines continues a comment on all lines of a multi-line value
+func commentLines(value string) string {
+	return strings.ReplaceAll(value, "\n", "\n  # ")
+}
@@ -0,0 +1,99 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"testing"
+
+	"terraform-provider-solacebroker/internal/broker"
+)
+
+func TestHclStringValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"Plain", "test", "\"test\""},
+		{"Escaped", "a \"quoted\" \\ ${var} %{if}", "\"a \\\"quoted\\\" \\\\ $${var} %%{if}\""},
+		{"SingleLine", "line\n", "\"line\\n\""},
+		{"NoTrailingNewline", "line1\nline2\nline3", "\"line1\\nline2\\nline3\""},
+		{"Heredoc", "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n", "<<EOT\n-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\nEOT"},
+		{"HeredocDelimiter", "a\nEOT\n${b}\n", "<<EOT_\na\nEOT\n$${b}\nEOT_"},
+		{"HeredocNonPrintable", "a\r\nb\r\n", "\"a\\r\\nb\\r\\n\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hclStringValue(tt.value); got != tt.want {
+				t.Errorf("hclStringValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHclObjectValue(t *testing.T) {
+	attributes := []*broker.AttributeInfo{
+		{SempName: "clearValue", TerraformName: "clear_value", BaseType: broker.Int64},
+		{SempName: "setValue", TerraformName: "set_value", BaseType: broker.Int64},
+		{SempName: "nested", TerraformName: "nested", BaseType: broker.Struct, Attributes: []*broker.AttributeInfo{
+			{SempName: "name", TerraformName: "name", BaseType: broker.String},
+			{SempName: "enabled", TerraformName: "enabled", BaseType: broker.Bool},
+		}},
+	}
+	value := map[string]any{"setValue": float64(3000000000), "clearValue": float64(10), "nested": map[string]any{"enabled": true, "name": "n\"1"}}
+	want := "{\n  clear_value = 10\n  set_value   = 3000000000\n  nested = {\n    name    = \"n\\\"1\"\n    enabled = true\n  }\n}"
+	if got := hclObjectValue(attributes, value); got != want {
+		t.Errorf("hclObjectValue() = %q, want %q", got, want)
+	}
+}
+
+func TestHclFormatResource(t *testing.T) {
+	got, err := hclFormatResource("solacebroker_msg_vpn_queue test_q", ResourceConfig{ResourceAttributes: map[string]ResourceAttributeInfo{
+		"queue_name":                      newAttributeInfo("\"q\""),
+		"msg_vpn_name":                    newAttributeInfo("solacebroker_msg_vpn.test.msg_vpn_name"),
+		"event_msg_spool_usage_threshold": addCommentToAttributeInfo(newAttributeInfo("{\n  clear_percent = 50\n  set_percent = 60\n}"), " # Note: This attribute is deprecated."),
+	}})
+	if err != nil {
+		t.Fatalf("hclFormatResource() error = %v", err)
+	}
+	want := "resource \"solacebroker_msg_vpn_queue\" \"test_q\" {\n" +
+		"  event_msg_spool_usage_threshold = {\n    clear_percent = 50\n    set_percent   = 60\n  } # Note: This attribute is deprecated.\n" +
+		"  msg_vpn_name = solacebroker_msg_vpn.test.msg_vpn_name\n" +
+		"  queue_name   = \"q\"\n" +
+		"}"
+	if got != want {
+		t.Errorf("hclFormatResource() =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := hclFormatResource("solacebroker_msg_vpn test", ResourceConfig{ResourceAttributes: map[string]ResourceAttributeInfo{
+		"msg_vpn_name": newAttributeInfo("\"unterminated"),
+	}}); err == nil {
+		t.Errorf("hclFormatResource() with an invalid value succeeded")
+	}
+}
+
+func TestFormatHcl(t *testing.T) {
+	got, err := formatHcl("test.tf", []byte("variable \"a\" {\n type = string\n  # default = <<EOT\n  # x\n  # EOT\n}\n"))
+	if err != nil {
+		t.Fatalf("formatHcl() error = %v", err)
+	}
+	if want := "variable \"a\" {\n  type = string\n  # default = <<EOT\n  # x\n  # EOT\n}\n"; string(got) != want {
+		t.Errorf("formatHcl() = %q, want %q", got, want)
+	}
+	if _, err := formatHcl("test.tf", []byte("resource \"a\" {\n")); err == nil {
+		t.Errorf("formatHcl() with invalid configuration succeeded")
+	}
+}
@@ -0,0 +1,267 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// terraformJSON is a configuration in the Terraform JSON configuration syntax, with the block types in the order of
+// the HCL template
+type terraformJSON struct {
+	Terraform map[string]any            `json:"terraform,omitempty"`
+	Variable  map[string]any            `json:"variable,omitempty"`
+	Provider  map[string]any            `json:"provider,omitempty"`
+	Resource  map[string]map[string]any `json:"resource,omitempty"`
+	Import    []map[string]any          `json:"import,omitempty"`
+	Output    map[string]any            `json:"output,omitempty"`
+}
+
+// writeJSONSections writes the named sections of the Terraform template to a file in the JSON configuration syntax.
+// The provider sections are converted from the template, the other sections are built from the object info.
+func writeJSONSections(fileName string, terraformObjectInfo *ObjectInfo, sections ...string) error {
+	config := &terraformJSON{}
+	for _, section := range sections {
+		var err error
+		switch section {
+		case "variables":
+			config.addVariables(terraformObjectInfo.Variables)
+		case "outputs":
+			err = config.addOutputs(terraformObjectInfo.Outputs)
+		case "resources":
+			err = config.addResources(terraformObjectInfo.Resources, terraformObjectInfo.ImportBlocks)
+		default:
+			err = config.addTemplateSection(section, terraformObjectInfo)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(config); err != nil {
+		return err
+	}
+	return os.WriteFile(fileName, content.Bytes(), 0664)
+}
+
+// addTemplateSection adds the blocks of a section of the Terraform template
+func (c *terraformJSON) addTemplateSection(section string, terraformObjectInfo *ObjectInfo) error {
+	var codeStream bytes.Buffer
+	if err := terraformTemplate.ExecuteTemplate(&codeStream, section, terraformObjectInfo); err != nil {
+		return err
+	}
+	file, diags := hclsyntax.ParseConfig(codeStream.Bytes(), section, hcl.InitialPos)
+	if diags.HasErrors() {
+		return fmt.Errorf("template section %s is invalid: %w", section, diags)
+	}
+	for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+		content, err := hclBodyJSON(block.Body, file.Bytes)
+		if err != nil {
+			return err
+		}
+		switch block.Type {
+		case "terraform":
+			c.Terraform = content
+		case "variable":
+			// Type constraints are type expressions, not templates, also in the JSON syntax
+			if typeAttribute, ok := block.Body.Attributes["type"]; ok {
+				content["type"] = string(typeAttribute.Expr.Range().SliceBytes(file.Bytes))
+			}
+			c.Variable = setJSONBlock(c.Variable, block.Labels[0], content)
+		case "provider":
+			c.Provider = setJSONBlock(c.Provider, block.Labels[0], content)
+		default:
+			return fmt.Errorf("unexpected %s block in template section %s", block.Type, section)
+		}
+	}
+	return nil
+}
+
+func (c *terraformJSON) addVariables(variables map[string]VariableConfig) {
+	for name, variable := range variables {
+		content := map[string]any{"type": variable.Type}
+		if variable.Sensitive {
+			content["sensitive"] = true
+		} else if variable.Default != "" {
+			// There are no comments in JSON, Terraform ignores "//" properties instead
+			content["//"] = "default = " + variable.Default
+		}
+		c.Variable = setJSONBlock(c.Variable, name, content)
+	}
+}
+
+func (c *terraformJSON) addOutputs(outputs map[string]string) error {
+	for name, value := range outputs {
+		jsonValue, err := hclExpressionJSON(value)
+		if err != nil {
+			return fmt.Errorf("invalid value of output %s: %w", name, err)
+		}
+		c.Output = setJSONBlock(c.Output, name, map[string]any{"value": jsonValue})
+	}
+	return nil
+}
+
+// addResources adds the resources and, if requested, their import blocks in the order of the resources
+func (c *terraformJSON) addResources(brokerResources []map[string]ResourceConfig, importBlocks bool) error {
+	for _, resources := range brokerResources {
+		for resourceTypeAndName, resourceConfig := range resources {
+			typeAndName := strings.SplitN(resourceTypeAndName, " ", 2)
+			content := map[string]any{}
+			var comments []string
+			for attributeName, info := range resourceConfig.ResourceAttributes {
+				value, err := hclExpressionJSON(info.AttributeValue)
+				if err != nil {
+					return fmt.Errorf("invalid value of %s attribute %s: %w", resourceTypeAndName, attributeName, err)
+				}
+				content[attributeName] = value
+				if comment := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(info.Comment), "#")); comment != "" {
+					comments = append(comments, attributeName+": "+comment)
+				}
+			}
+			if len(comments) > 0 {
+				sort.Strings(comments)
+				content["//"] = strings.Join(comments, "\n")
+			}
+			if c.Resource == nil {
+				c.Resource = map[string]map[string]any{}
+			}
+			if c.Resource[typeAndName[0]] == nil {
+				c.Resource[typeAndName[0]] = map[string]any{}
+			}
+			c.Resource[typeAndName[0]][typeAndName[1]] = content
+			if importBlocks && resourceConfig.ImportId != "" {
+				c.Import = append(c.Import, map[string]any{
+					"to": typeAndName[0] + "." + typeAndName[1],
+					"id": escapeHclTemplate(resourceConfig.ImportId),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+func setJSONBlock(blocks map[string]any, label string, content map[string]any) map[string]any {
+	if blocks == nil {
+		blocks = map[string]any{}
+	}
+	blocks[label] = content
+	return blocks
+}
+
+// hclBodyJSON converts the attributes and nested blocks of a body to the JSON configuration syntax
+func hclBodyJSON(body *hclsyntax.Body, source []byte) (map[string]any, error) {
+	content := map[string]any{}
+	for name, attribute := range body.Attributes {
+		value, err := hclExpressionJSON(string(attribute.Expr.Range().SliceBytes(source)))
+		if err != nil {
+			return nil, err
+		}
+		content[name] = value
+	}
+	for _, block := range body.Blocks {
+		blockContent, err := hclBodyJSON(block.Body, source)
+		if err != nil {
+			return nil, err
+		}
+		// Labels are nested objects in the JSON syntax
+		parent, key := content, block.Type
+		for _, label := range block.Labels {
+			child, ok := parent[key].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[key] = child
+			}
+			parent, key = child, label
+		}
+		parent[key] = blockContent
+	}
+	return content, nil
+}
+
+// hclExpressionJSON converts an HCL expression to its JSON value. Literal values are converted to the corresponding
+// JSON values, strings are escaped as JSON strings are templates. Other expressions, such as references, are
+// converted to template strings with "${...}" interpolations.
+func hclExpressionJSON(expression string) (any, error) {
+	// A heredoc ends with a newline
+	source := []byte(expression + "\n")
+	expr, diags := hclsyntax.ParseExpression(source, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if len(expr.Variables()) == 0 {
+		value, diags := expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return ctyValueJSON(value), nil
+	}
+	switch expr := expr.(type) {
+	case *hclsyntax.TemplateExpr:
+		var sb strings.Builder
+		for _, part := range expr.Parts {
+			if literal, ok := part.(*hclsyntax.LiteralValueExpr); ok && literal.Val.Type() == cty.String {
+				sb.WriteString(escapeHclTemplate(literal.Val.AsString()))
+			} else {
+				sb.WriteString("${" + string(part.Range().SliceBytes(source)) + "}")
+			}
+		}
+		return sb.String(), nil
+	case *hclsyntax.TemplateWrapExpr:
+		return "${" + string(expr.Wrapped.Range().SliceBytes(source)) + "}", nil
+	}
+	return "${" + strings.TrimSpace(expression) + "}", nil
+}
+
+// ctyValueJSON converts a known value to its JSON value
+func ctyValueJSON(value cty.Value) any {
+	switch {
+	case value.IsNull():
+		return nil
+	case value.Type() == cty.String:
+		return escapeHclTemplate(value.AsString())
+	case value.Type() == cty.Number:
+		return json.Number(value.AsBigFloat().Text('f', -1))
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type().IsObjectType() || value.Type().IsMapType():
+		result := map[string]any{}
+		for it := value.ElementIterator(); it.Next(); {
+			key, element := it.Element()
+			result[key.AsString()] = ctyValueJSON(element)
+		}
+		return result
+	case value.CanIterateElements():
+		result := []any{}
+		for it := value.ElementIterator(); it.Next(); {
+			_, element := it.Element()
+			result = append(result, ctyValueJSON(element))
+		}
+		return result
+	}
+	return nil
+}
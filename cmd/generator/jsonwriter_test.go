@@ -0,0 +1,162 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	hcljson "github.com/hashicorp/hcl/v2/json"
+)
+
+func TestHclExpressionJSON(t *testing.T) {
+	tests := []struct {
+		expression string
+		want       any
+	}{
+		{"\"test\"", "test"},
+		{"\"a $${b}\"", "a $${b}"},
+		{"true", true},
+		{"3000000000", json.Number("3000000000")},
+		{"{\n  clear_percent = 50\n  set_percent   = 60\n}", map[string]any{"clear_percent": json.Number("50"), "set_percent": json.Number("60")}},
+		{"<<EOT\nline1\nline2\nEOT", "line1\nline2\n"},
+		{"solacebroker_msg_vpn.test.msg_vpn_name", "${solacebroker_msg_vpn.test.msg_vpn_name}"},
+		{"var.msg_vpn_name", "${var.msg_vpn_name}"},
+		{"\"https://${var.host}:943/${b\"", nil},
+		{"\"https://${var.host}:943/%%{x}\"", "https://${var.host}:943/%%{x}"},
+		{"\"${var.host}\"", "${var.host}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			got, err := hclExpressionJSON(tt.expression)
+			if tt.want == nil {
+				if err == nil {
+					t.Errorf("hclExpressionJSON() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("hclExpressionJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hclExpressionJSON() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTerraformFileJSON(t *testing.T) {
+	brokerResources := []map[string]ResourceConfig{
+		{"solacebroker_msg_vpn test": {ResourceAttributes: map[string]ResourceAttributeInfo{
+			"msg_vpn_name":                     newAttributeInfo("\"test\""),
+			"authentication_basic_type":        addCommentToAttributeInfo(newAttributeInfo("\"internal\""), " # Note: This attribute is deprecated."),
+			"event_connection_count_threshold": newAttributeInfo("{\n  clear_percent = 50\n  set_percent   = 70\n}"),
+		}, ImportId: "test"}},
+		{"solacebroker_msg_vpn_queue test_q1": {ResourceAttributes: map[string]ResourceAttributeInfo{
+			"msg_vpn_name":   newAttributeInfo("solacebroker_msg_vpn.test.msg_vpn_name"),
+			"queue_name":     newAttributeInfo("\"q1\""),
+			"max_bind_count": newAttributeInfo("10"),
+		}, ImportId: "test/q1"}},
+	}
+	fileName := t.TempDir() + "/config.tf.json"
+	err := GenerateTerraformFile(&ObjectInfo{
+		BasicAuthentication: true,
+		FileName:            fileName,
+		ImportBlocks:        true,
+		OutputFormat:        "json",
+		Resources:           brokerResources,
+		Variables:           map[string]VariableConfig{"password": {Type: "string", Sensitive: true}, "client_username": {Type: "string", Default: "\"user\""}},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTerraformFile() error = %v", err)
+	}
+	content, _ := os.ReadFile(fileName)
+	if _, diags := hcljson.Parse(content, fileName); diags.HasErrors() {
+		t.Fatalf("generated configuration is invalid: %v\n%s", diags, content)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"terraform": map[string]any{"required_providers": map[string]any{"solacebroker": map[string]any{"source": "registry.terraform.io/solaceproducts/solacebroker"}}},
+		"variable": map[string]any{
+			"broker_url":      map[string]any{"type": "string", "description": "The URL of the Solace broker."},
+			"broker_username": map[string]any{"type": "string", "description": "The management username of the Solace broker."},
+			"broker_password": map[string]any{"type": "string", "description": "The management password of the Solace broker."},
+			"password":        map[string]any{"type": "string", "sensitive": true},
+			"client_username": map[string]any{"type": "string", "//": "default = \"user\""},
+		},
+		"provider": map[string]any{"solacebroker": map[string]any{"url": "${var.broker_url}", "username": "${var.broker_username}", "password": "${var.broker_password}"}},
+		"resource": map[string]any{
+			"solacebroker_msg_vpn": map[string]any{"test": map[string]any{
+				"msg_vpn_name":                     "test",
+				"authentication_basic_type":        "internal",
+				"event_connection_count_threshold": map[string]any{"clear_percent": float64(50), "set_percent": float64(70)},
+				"//":                               "authentication_basic_type: Note: This attribute is deprecated.",
+			}},
+			"solacebroker_msg_vpn_queue": map[string]any{"test_q1": map[string]any{
+				"msg_vpn_name":   "${solacebroker_msg_vpn.test.msg_vpn_name}",
+				"queue_name":     "q1",
+				"max_bind_count": float64(10),
+			}},
+		},
+		"import": []any{
+			map[string]any{"to": "solacebroker_msg_vpn.test", "id": "test"},
+			map[string]any{"to": "solacebroker_msg_vpn_queue.test_q1", "id": "test/q1"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GenerateTerraformFile() =\n%s\nwant %v", content, want)
+	}
+}
+
+func TestGenerateTerraformDirectoryJSON(t *testing.T) {
+	directory := t.TempDir()
+	err := GenerateTerraformDirectory(&ObjectInfo{
+		FileName:        directory,
+		Module:          true,
+		OutputFormat:    "json",
+		BrokerResources: []map[string]string{{"solacebroker_msg_vpn myvpn": ""}},
+		Resources:       []map[string]ResourceConfig{{"solacebroker_msg_vpn myvpn": {ResourceAttributes: map[string]ResourceAttributeInfo{"msg_vpn_name": newAttributeInfo("var.msg_vpn_name")}}}},
+		Variables:       map[string]VariableConfig{"msg_vpn_name": {Type: "string", Default: "\"test\""}},
+		Outputs:         map[string]string{"msg_vpn_name": "solacebroker_msg_vpn.myvpn.msg_vpn_name"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTerraformDirectory() error = %v", err)
+	}
+	for fileName, want := range map[string]string{
+		"providers.tf.json": `{"terraform":{"required_providers":{"solacebroker":{"source":"registry.terraform.io/solaceproducts/solacebroker"}}}}`,
+		"variables.tf.json": `{"variable":{"msg_vpn_name":{"//":"default = \"test\"","type":"string"}}}`,
+		"outputs.tf.json":   `{"output":{"msg_vpn_name":{"value":"${solacebroker_msg_vpn.myvpn.msg_vpn_name}"}}}`,
+		"msg_vpn.tf.json":   `{"resource":{"solacebroker_msg_vpn":{"myvpn":{"msg_vpn_name":"${var.msg_vpn_name}"}}}}`,
+	} {
+		t.Run(fileName, func(t *testing.T) {
+			content, err := os.ReadFile(directory + "/" + fileName)
+			if err != nil {
+				t.Fatalf("file not generated: %v", err)
+			}
+			var got, wantValue any
+			_ = json.Unmarshal(content, &got)
+			_ = json.Unmarshal([]byte(want), &wantValue)
+			if !reflect.DeepEqual(got, wantValue) {
+				t.Errorf("%v = %s, want %s", fileName, content, want)
+			}
+		})
+	}
+}
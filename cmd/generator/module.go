@@ -0,0 +1,158 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"fmt"
+	"strings"
+	internalbroker "terraform-provider-solacebroker/internal/broker"
+)
+
+// ModuleVariable is a substitution rule for module generation: string attribute values containing Value are
+// parameterized by the module input variable Name
+type ModuleVariable struct {
+	Name  string
+	Value string
+}
+
+// ParseModuleVariables parses a comma-separated list of substitution rules in the form <name>=<value>
+func ParseModuleVariables(rules string) ([]ModuleVariable, error) {
+	var moduleVariables []ModuleVariable
+	for _, rule := range strings.Split(rules, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		name, value, found := strings.Cut(rule, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("module variable %q is not in the form <name>=<value>", rule)
+		}
+		if !IsValidTerraformIdentifier(name) {
+			return nil, fmt.Errorf("module variable name %q is not a valid Terraform identifier", name)
+		}
+		moduleVariables = append(moduleVariables, ModuleVariable{Name: name, Value: value})
+	}
+	return moduleVariables, nil
+}
+
+// parameterizeForModule replaces environment specific values in brokerResources by references to module input
+// variables: the identifying attributes of the root object, which is the first resource, and any string values that
+// match the moduleVariables. Returns the input variables and the outputs of the module, and the names of the
+// moduleVariables whose values were not found.
+func parameterizeForModule(brokerResources []map[string]ResourceConfig, rootBrokerObjectType BrokerObjectType, moduleVariables []ModuleVariable) (map[string]VariableConfig, map[string]string, []string) {
+	inputs := map[string]VariableConfig{}
+	outputs := map[string]string{}
+	var unused []string
+	if len(brokerResources) == 0 {
+		return inputs, outputs, unused
+	}
+	for rootResourceTypeAndName, rootResource := range brokerResources[0] {
+		for _, attr := range internalbroker.Entities[dsLookup[rootBrokerObjectType]].Attributes {
+			info, ok := rootResource.ResourceAttributes[attr.TerraformName]
+			if !attr.Identifying || !ok {
+				continue
+			}
+			attrType, _, _ := GetBaseTypeAndDefault(attr)
+			inputs[attr.TerraformName] = VariableConfig{
+				Type:    attrType,
+				Default: info.AttributeValue,
+			}
+			rootResource.ResourceAttributes[attr.TerraformName] = addCommentToAttributeInfo(newAttributeInfo("var."+attr.TerraformName), info.Comment)
+			outputs[attr.TerraformName] = strings.Replace(rootResourceTypeAndName, " ", ".", 1) + "." + attr.TerraformName
+		}
+	}
+	found := map[string]bool{}
+	for _, resources := range brokerResources {
+		for _, resourceConfig := range resources {
+			for attrName, info := range resourceConfig.ResourceAttributes {
+				// Only string values are considered, they are matched as returned by SEMP
+				stringValue, ok := hclStringLiteral(info.AttributeValue)
+				if !ok {
+					continue
+				}
+				parts := substituteModuleVariables(stringValue, moduleVariables)
+				if len(parts) == 1 && parts[0].variable == "" {
+					continue
+				}
+				for _, part := range parts {
+					if part.variable != "" {
+						found[part.variable] = true
+					}
+				}
+				info.AttributeValue = moduleVariablesTemplate(parts)
+				resourceConfig.ResourceAttributes[attrName] = info
+			}
+		}
+	}
+	for _, moduleVariable := range moduleVariables {
+		if !found[moduleVariable.Name] {
+			unused = append(unused, moduleVariable.Name)
+		}
+		inputs[moduleVariable.Name] = VariableConfig{
+			Type:    "string",
+			Default: "\"" + SanitizeHclStringValue(moduleVariable.Value) + "\"",
+		}
+	}
+	return inputs, outputs, unused
+}
+
+// templatePart is either literal text or a reference to a module variable
+type templatePart struct {
+	text     string
+	variable string
+}
+
+// substituteModuleVariables splits a string value into literal text and the module variables whose values it
+// contains. The variables are matched in order, text matched by an earlier variable is not matched again.
+func substituteModuleVariables(value string, moduleVariables []ModuleVariable) []templatePart {
+	parts := []templatePart{{text: value}}
+	for _, moduleVariable := range moduleVariables {
+		var substituted []templatePart
+		for _, part := range parts {
+			if part.variable != "" || !strings.Contains(part.text, moduleVariable.Value) {
+				substituted = append(substituted, part)
+				continue
+			}
+			for i, text := range strings.Split(part.text, moduleVariable.Value) {
+				if i > 0 {
+					substituted = append(substituted, templatePart{variable: moduleVariable.Name})
+				}
+				if text != "" {
+					substituted = append(substituted, templatePart{text: text})
+				}
+			}
+		}
+		parts = substituted
+	}
+	return parts
+}
+
+// moduleVariablesTemplate returns the HCL expression of the parts: a variable reference if the value is a single
+// variable, otherwise a quoted string template with the literal text escaped
+func moduleVariablesTemplate(parts []templatePart) string {
+	if len(parts) == 1 && parts[0].variable != "" {
+		return "var." + parts[0].variable
+	}
+	template := "\""
+	for _, part := range parts {
+		if part.variable != "" {
+			template += "${var." + part.variable + "}"
+		} else {
+			template += SanitizeHclStringValue(part.text)
+		}
+	}
+	return template + "\""
+}
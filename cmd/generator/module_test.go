@@ -0,0 +1,129 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseModuleVariables(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		want    []ModuleVariable
+		wantErr bool
+	}{
+		{"Empty", "", nil, false},
+		{"Multiple", "host=dev.example.com, url=http://dev:9000/a=b", []ModuleVariable{{"host", "dev.example.com"}, {"url", "http://dev:9000/a=b"}}, false},
+		{"MissingValue", "host=", nil, true},
+		{"MissingSeparator", "host", nil, true},
+		{"InvalidName", "1host=dev", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseModuleVariables(tt.rules)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseModuleVariables() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseModuleVariables() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParameterizeForModule(t *testing.T) {
+	createBrokerObjectRelationships()
+	brokerResources := []map[string]ResourceConfig{
+		{"solacebroker_msg_vpn myvpn": {ResourceAttributes: map[string]ResourceAttributeInfo{
+			"msg_vpn_name": newAttributeInfo("\"test\""),
+			"enabled":      newAttributeInfo("true"),
+		}}},
+		{"solacebroker_msg_vpn_bridge_remote_msg_vpn myvpn_b": {ResourceAttributes: map[string]ResourceAttributeInfo{
+			"msg_vpn_name":            newAttributeInfo("solacebroker_msg_vpn.myvpn.msg_vpn_name"),
+			"remote_msg_vpn_location": newAttributeInfo("\"v:dev.example.com:55555\""),
+			"tls_server_name":         newAttributeInfo("\"dev.example.com\""),
+		}}},
+	}
+	inputs, outputs, unused := parameterizeForModule(brokerResources, "msg_vpn", []ModuleVariable{{"host", "dev.example.com"}, {"unused", "none"}})
+	wantAttributes := map[string]map[string]string{
+		"solacebroker_msg_vpn myvpn": {
+			"msg_vpn_name": "var.msg_vpn_name",
+			"enabled":      "true",
+		},
+		"solacebroker_msg_vpn_bridge_remote_msg_vpn myvpn_b": {
+			"msg_vpn_name":            "solacebroker_msg_vpn.myvpn.msg_vpn_name",
+			"remote_msg_vpn_location": "\"v:${var.host}:55555\"",
+			"tls_server_name":         "var.host",
+		},
+	}
+	for _, resources := range brokerResources {
+		for resourceTypeAndName, resourceConfig := range resources {
+			for attrName, want := range wantAttributes[resourceTypeAndName] {
+				if got := resourceConfig.ResourceAttributes[attrName].AttributeValue; got != want {
+					t.Errorf("parameterizeForModule() set %v %v to %v, want %v", resourceTypeAndName, attrName, got, want)
+				}
+			}
+		}
+	}
+	wantInputs := map[string]VariableConfig{
+		"msg_vpn_name": {Type: "string", Default: "\"test\""},
+		"host":         {Type: "string", Default: "\"dev.example.com\""},
+		"unused":       {Type: "string", Default: "\"none\""},
+	}
+	if !reflect.DeepEqual(inputs, wantInputs) {
+		t.Errorf("parameterizeForModule() inputs = %v, want %v", inputs, wantInputs)
+	}
+	wantOutputs := map[string]string{"msg_vpn_name": "solacebroker_msg_vpn.myvpn.msg_vpn_name"}
+	if !reflect.DeepEqual(outputs, wantOutputs) {
+		t.Errorf("parameterizeForModule() outputs = %v, want %v", outputs, wantOutputs)
+	}
+	if !reflect.DeepEqual(unused, []string{"unused"}) {
+		t.Errorf("parameterizeForModule() unused = %v, want [unused]", unused)
+	}
+}
+
+func TestParameterizeForModuleEscapedValues(t *testing.T) {
+	createBrokerObjectRelationships()
+	attributes := map[string]ResourceAttributeInfo{
+		"description":     newAttributeInfo(hclStringValue("a\nb")),
+		"tls_server_name": newAttributeInfo(hclStringValue("dev.example.com")),
+		"remote_address":  newAttributeInfo(hclStringValue("v:dev.example.com:55555")),
+		"template":        newAttributeInfo(hclStringValue("${var}/dev.example.com")),
+	}
+	brokerResources := []map[string]ResourceConfig{
+		{"solacebroker_msg_vpn_queue myvpn_q": {ResourceAttributes: attributes}},
+	}
+	// "n" is not in the value "a\nb" but in its escaped form, "var" is not in the values but in the references of the
+	// earlier variable
+	_, _, unused := parameterizeForModule(brokerResources, "msg_vpn_queue", []ModuleVariable{{"host", "dev.example.com"}, {"env", "n"}, {"name", "var"}})
+	want := map[string]string{
+		"description":     `"a\nb"`,
+		"tls_server_name": "var.host",
+		"remote_address":  `"v:${var.host}:55555"`,
+		"template":        `"$${${var.name}}/${var.host}"`,
+	}
+	for attrName, want := range want {
+		if got := attributes[attrName].AttributeValue; got != want {
+			t.Errorf("parameterizeForModule() set %v to %v, want %v", attrName, got, want)
+		}
+	}
+	if !reflect.DeepEqual(unused, []string{"env"}) {
+		t.Errorf("parameterizeForModule() unused = %v, want [env]", unused)
+	}
+}
@@ -16,7 +16,6 @@
 package generator
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -81,9 +80,9 @@ func processSempResults(resourceTypeAndName string, attributes []*broker.Attribu
 					continue
 				}
 				if len(valuesRes.(string)) > 0 {
-					systemProvisioned = isSystemProvisionedAttribute(valuesRes.(string))
+					systemProvisioned = broker.IsSystemProvisioned(valuesRes.(string))
 				}
-				val := "\"" + SanitizeHclStringValue(valuesRes.(string)) + "\""
+				val := hclStringValue(valuesRes.(string))
 				if reflect.TypeOf(attr.Default) != nil && fmt.Sprint(attr.Default) == fmt.Sprint(valuesRes) {
 					//attributes with default values will be added to the internal list but will be skipped from results
 					attributesWithDefaultValue[attr.TerraformName] = &val
@@ -95,7 +94,7 @@ func processSempResults(resourceTypeAndName string, attributes []*broker.Attribu
 					continue
 				}
 				intValue := valuesRes
-				val := fmt.Sprintf("%v", intValue)
+				val := hclNumberValue(intValue)
 				if reflect.TypeOf(attr.Default) != nil && fmt.Sprint(attr.Default) == fmt.Sprint(intValue) {
 					//attributes with default values will be skipped
 					attributesWithDefaultValue[attr.TerraformName] = &val
@@ -115,15 +114,11 @@ func processSempResults(resourceTypeAndName string, attributes []*broker.Attribu
 				}
 				resourceConfig.ResourceAttributes[attr.TerraformName] = newAttributeInfo(val)
 			case broker.Struct:
-				valueJson, err := json.Marshal(valuesRes)
-				if err != nil {
+				structValue, ok := valuesRes.(map[string]any)
+				if !ok {
 					continue
 				}
-				output := strings.ReplaceAll(string(valueJson), "clearPercent", "clear_percent")
-				output = strings.ReplaceAll(output, "setPercent", "set_percent")
-				output = strings.ReplaceAll(output, "clearValue", "clear_value")
-				output = strings.ReplaceAll(output, "setValue", "set_value")
-				val := output
+				val := hclObjectValue(attr.Attributes, structValue)
 				if reflect.TypeOf(attr.Default) != nil && fmt.Sprint(attr.Default) == fmt.Sprint(valuesRes) {
 					//attributes with default values will be skipped
 					attributesWithDefaultValue[attr.TerraformName] = &val
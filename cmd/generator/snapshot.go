@@ -0,0 +1,222 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package generator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"terraform-provider-solacebroker/internal/broker/generated"
+	"time"
+)
+
+// SnapshotFormatVersion is the version of the snapshot file format written, increased on incompatible changes
+const SnapshotFormatVersion = 1
+
+// Snapshot is the raw SEMP configuration of a broker object and all its child objects, as returned by the broker
+type Snapshot struct {
+	FormatVersion        int                 `json:"formatVersion"`
+	CreatedAt            time.Time           `json:"createdAt"`
+	GeneratorSempVersion string              `json:"generatorSempVersion"` // the SEMP version of the generator that took the snapshot
+	About                map[string]any      `json:"about"`                // the broker's /about/api response
+	ObjectType           string              `json:"objectType"`           // the resource type of the object, without the "solacebroker_" prefix
+	Identifier           string              `json:"identifier"`           // the provider-specific identifier of the object
+	Objects              *SnapshotCollection `json:"objects"`
+}
+
+// SnapshotCollection holds the objects of a type, either the snapshot's object or the child objects of an object
+type SnapshotCollection struct {
+	ObjectType string              `json:"objectType"`
+	Instances  []*SnapshotInstance `json:"instances"`
+}
+
+// SnapshotInstance is an object in a snapshot with its SEMP attributes and the collections of its child objects
+type SnapshotInstance struct {
+	Data     map[string]any        `json:"data"`
+	Children []*SnapshotCollection `json:"children,omitempty"`
+}
+
+// TakeSnapshot fetches the configuration of a broker object and all its child objects, including system provisioned
+// ones. The about information of the broker is stored along. Progress messages are logged to log, if not nil.
+func TakeSnapshot(context context.Context, client SempClient, brokerObjectType BrokerObjectType, identifier string, about map[string]any, parallelism int, log io.Writer) (*Snapshot, error) {
+	c, err := newBrokerConfigCrawler(client, brokerObjectType, "", crawlOptions{
+		parallelism:              parallelism,
+		includeSystemProvisioned: true,
+		log:                      log,
+	})
+	if err != nil {
+		return nil, err
+	}
+	collection, err := c.fetchCollection(context, brokerObjectType, identifier, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Snapshot{
+		FormatVersion:        SnapshotFormatVersion,
+		CreatedAt:            time.Now().UTC(),
+		GeneratorSempVersion: generated.SempVersion,
+		About:                about,
+		ObjectType:           string(brokerObjectType),
+		Identifier:           identifier,
+		Objects:              toSnapshotCollection(collection),
+	}, nil
+}
+
+func toSnapshotCollection(collection *fetchedCollection) *SnapshotCollection {
+	snapshotCollection := &SnapshotCollection{
+		ObjectType: string(collection.brokerObjectType),
+		Instances:  []*SnapshotInstance{},
+	}
+	for _, instance := range collection.instances {
+		snapshotInstance := &SnapshotInstance{Data: instance.result}
+		// Keep the children in a deterministic order
+		for _, subType := range brokerObjectRelationship[collection.brokerObjectType] {
+			if childCollection, ok := instance.children[subType]; ok {
+				snapshotInstance.Children = append(snapshotInstance.Children, toSnapshotCollection(childCollection))
+			}
+		}
+		snapshotCollection.Instances = append(snapshotCollection.Instances, snapshotInstance)
+	}
+	return snapshotCollection
+}
+
+// WriteSnapshot writes a snapshot as JSON, compressed with gzip if the file name ends with ".gz"
+func WriteSnapshot(snapshot *Snapshot, fileName string) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(fileName, ".gz") {
+		var buffer bytes.Buffer
+		writer := gzip.NewWriter(&buffer)
+		if _, err := writer.Write(data); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+		data = buffer.Bytes()
+	}
+	return os.WriteFile(fileName, data, 0664)
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot
+func ReadSnapshot(fileName string) (*Snapshot, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var reader io.Reader = file
+	if strings.HasSuffix(fileName, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	snapshot := &Snapshot{}
+	if err := json.NewDecoder(reader).Decode(snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot file: %w", err)
+	}
+	if snapshot.FormatVersion != SnapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d, expected %d", snapshot.FormatVersion, SnapshotFormatVersion)
+	}
+	if snapshot.Objects == nil || snapshot.ObjectType != snapshot.Objects.ObjectType || len(snapshot.Objects.Instances) != 1 {
+		return nil, fmt.Errorf("invalid snapshot file: the snapshot must contain exactly one %s object", snapshot.ObjectType)
+	}
+	return snapshot, nil
+}
+
+// snapshotBrokerConfig generates the config for the object of a snapshot and its child objects, as fetchBrokerConfig
+// does from the broker
+func snapshotBrokerConfig(snapshot *Snapshot, brokerResourceName string, options crawlOptions) (*brokerConfig, error) {
+	c, err := newBrokerConfigCrawler(nil, BrokerObjectType(snapshot.ObjectType), brokerResourceName, options)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := c.snapshotCollection(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	err = c.processCollection(collection, BrokerObjectInstanceInfo{})
+	if err != nil {
+		return nil, err
+	}
+	return c.config, nil
+}
+
+// snapshotCollection returns the object of a snapshot with its child objects, as fetchCollection does from the broker
+func (c *brokerConfigCrawler) snapshotCollection(snapshot *Snapshot) (*fetchedCollection, error) {
+	brokerObjectType := BrokerObjectType(snapshot.ObjectType)
+	identifyingAttributes, err := identifierToBrokerObjectAttributes(brokerObjectType, snapshot.Identifier)
+	if err != nil {
+		return nil, err
+	}
+	collection := &fetchedCollection{
+		brokerObjectType: brokerObjectType,
+		instances: []*fetchedInstance{{
+			result:                snapshot.Objects.Instances[0].Data,
+			identifyingAttributes: identifyingAttributes,
+		}},
+	}
+	err = c.fetchChildCollectionsFromSnapshot(collection, snapshot.Objects)
+	if err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// fetchChildCollectionsFromSnapshot populates the child objects of the instances in the collection from the snapshot,
+// applying the same filters as fetching from the broker
+func (c *brokerConfigCrawler) fetchChildCollectionsFromSnapshot(collection *fetchedCollection, snapshotCollection *SnapshotCollection) error {
+	for i, instance := range collection.instances {
+		if instance.filtered || instance.systemProvisioned {
+			continue
+		}
+		instance.children = map[BrokerObjectType]*fetchedCollection{}
+		for _, snapshotChildCollection := range snapshotCollection.Instances[i].Children {
+			subType := BrokerObjectType(snapshotChildCollection.ObjectType)
+			if _, ok := dsLookup[subType]; !ok {
+				c.config.warn(fmt.Sprintf("Resource %s unknown to the generator, check snapshot and generator SEMP versions", subType))
+				continue
+			}
+			if c.options.filters.SkipType(subType) {
+				continue
+			}
+			_, childIdentifierAttributes, _, err := getAllInstancesPathTemplate(subType)
+			if err != nil {
+				return err
+			}
+			childCollection := &fetchedCollection{brokerObjectType: subType}
+			for _, snapshotInstance := range snapshotChildCollection.Instances {
+				childCollection.instances = append(childCollection.instances, c.newFetchedInstance(subType, snapshotInstance.Data, instance.identifyingAttributes, childIdentifierAttributes))
+			}
+			err = c.fetchChildCollectionsFromSnapshot(childCollection, snapshotChildCollection)
+			if err != nil {
+				return err
+			}
+			instance.children[subType] = childCollection
+		}
+	}
+	return nil
+}
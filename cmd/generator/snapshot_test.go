@@ -0,0 +1,121 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-solacebroker/internal/broker"
+	"terraform-provider-solacebroker/internal/broker/sempmock"
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func TestSnapshotBrokerConfig(t *testing.T) {
+	mockBroker := sempmock.New(sempmock.PageSize(2))
+	defer mockBroker.Close()
+	for path, data := range map[string]map[string]any{
+		"/msgVpns/test":                               {"enabled": true},
+		"/msgVpns/test/queues/q1":                     {"maxBindCount": 10},
+		"/msgVpns/test/queues/q2":                     {},
+		"/msgVpns/test/queues/q1/subscriptions/a%2Fb": {},
+		"/msgVpns/test/aclProfiles/acl":               {},
+		"/msgVpns/test/clientUsernames/user":          {"password": "secret"},
+	} {
+		if err := mockBroker.SetObject(path, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	client := semp.NewClient(mockBroker.URL+broker.SempDetail.BasePath, false, false, semp.Retries(0, 0, 0), semp.RequestLimits(semp.DefaultRequestTimeout, 0), semp.BasicAuth("admin", "admin"))
+	createBrokerObjectRelationships()
+	about := map[string]any{"platform": "VMR", "sempVersion": "2.39"}
+	snapshot, err := TakeSnapshot(context.Background(), client, "msg_vpn", "test", about, 4, nil)
+	if err != nil {
+		t.Fatalf("TakeSnapshot() error = %v", err)
+	}
+	filters, err := NewFilters(nil, []string{"msg_vpn_acl_profile"}, nil, nil)
+	if err != nil {
+		t.Fatalf("NewFilters() error = %v", err)
+	}
+	for _, fileName := range []string{"snapshot.json", "snapshot.json.gz"} {
+		t.Run(fileName, func(t *testing.T) {
+			fileName := filepath.Join(t.TempDir(), fileName)
+			if err := WriteSnapshot(snapshot, fileName); err != nil {
+				t.Fatalf("WriteSnapshot() error = %v", err)
+			}
+			readSnapshot, err := ReadSnapshot(fileName)
+			if err != nil {
+				t.Fatalf("ReadSnapshot() error = %v", err)
+			}
+			if readSnapshot.ObjectType != "msg_vpn" || readSnapshot.Identifier != "test" || readSnapshot.About["sempVersion"] != "2.39" {
+				t.Errorf("ReadSnapshot() returned snapshot of %v %v, about %v", readSnapshot.ObjectType, readSnapshot.Identifier, readSnapshot.About)
+			}
+			for _, options := range []crawlOptions{{}, {filters: filters}} {
+				want, err := fetchBrokerConfig(context.Background(), client, "msg_vpn", "test", "test", options)
+				if err != nil {
+					t.Fatalf("fetchBrokerConfig() error = %v", err)
+				}
+				got, err := snapshotBrokerConfig(readSnapshot, "test", options)
+				if err != nil {
+					t.Fatalf("snapshotBrokerConfig() error = %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("snapshotBrokerConfig() = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot Snapshot
+		wantErr  string
+	}{
+		{
+			"UnsupportedVersion",
+			Snapshot{FormatVersion: SnapshotFormatVersion + 1, ObjectType: "msg_vpn", Objects: &SnapshotCollection{ObjectType: "msg_vpn", Instances: []*SnapshotInstance{{}}}},
+			"unsupported snapshot format version",
+		},
+		{
+			"MissingObject",
+			Snapshot{FormatVersion: SnapshotFormatVersion, ObjectType: "msg_vpn", Objects: &SnapshotCollection{ObjectType: "msg_vpn"}},
+			"exactly one msg_vpn object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fileName := filepath.Join(t.TempDir(), "snapshot.json")
+			if err := os.WriteFile(fileName, data, 0664); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ReadSnapshot(fileName); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ReadSnapshot() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
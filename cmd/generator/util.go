@@ -16,18 +16,19 @@
 package generator
 
 import (
-	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"sort"
 	"strconv"
 	"strings"
-	"text/tabwriter"
 	"time"
 	"unicode"
 
 	"terraform-provider-solacebroker/internal/semp"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 )
 
 type CliParams struct {
@@ -35,13 +36,33 @@ type CliParams struct {
 	Username                 *string
 	Password                 *string
 	Bearer_token             *string
+	Oauth_token_url          *string
+	Oauth_client_id          *string
+	Oauth_client_secret      *string
+	Oauth_scopes             *string
+	Client_certificate       *string
+	Client_certificate_file  *string
+	Client_private_key       *string
+	Client_private_key_file  *string
 	Retries                  *int64
 	Retry_min_interval       *time.Duration
 	Retry_max_interval       *time.Duration
 	Request_timeout_duration *time.Duration
 	Request_min_interval     *time.Duration
 	Insecure_skip_verify     *bool
+	Ca_certificate           *string
+	Ca_bundle_file           *string
+	Tls_server_name          *string
 	Skip_api_check           *bool
+	Import_blocks            *bool
+	Module                   *bool
+	Module_variables         *string
+	Include_type             *[]string
+	Exclude_type             *[]string
+	Include_name             *[]string
+	Exclude_name             *[]string
+	Parallelism              *int64
+	Output_format            *string
 }
 
 type Color string
@@ -82,114 +103,250 @@ var idNo = []*unicode.RangeTable{
 	unicode.Pattern_White_Space,
 }
 
-func UpdateCliParamsWithEnv(cliParams CliParams) CliParams {
-	cliParams.Url = StringParamWithEnv("url", cliParams.Url, true, "")
-	cliParams.Username = StringParamWithEnv("username", cliParams.Username, false, "")
-	cliParams.Password = StringParamWithEnv("password", cliParams.Password, false, "")
-	cliParams.Bearer_token = StringParamWithEnv("bearer_token", cliParams.Bearer_token, false, "")
+// UpdateCliParamsWithEnv complements the params with environment variables and defaults, and verifies them
+func UpdateCliParamsWithEnv(cliParams CliParams) (CliParams, error) {
+	p := &paramsWithEnv{}
+	cliParams.Url = p.string("url", cliParams.Url, true, "")
+	cliParams.Username = p.string("username", cliParams.Username, false, "")
+	cliParams.Password = p.string("password", cliParams.Password, false, "")
+	cliParams.Bearer_token = p.string("bearer_token", cliParams.Bearer_token, false, "")
+	cliParams.Oauth_token_url = p.string("oauth_token_url", cliParams.Oauth_token_url, false, "")
+	cliParams.Oauth_client_id = p.string("oauth_client_id", cliParams.Oauth_client_id, false, "")
+	cliParams.Oauth_client_secret = p.string("oauth_client_secret", cliParams.Oauth_client_secret, false, "")
+	cliParams.Oauth_scopes = p.string("oauth_scopes", cliParams.Oauth_scopes, false, "")
+	if p.err != nil {
+		return cliParams, p.err
+	}
 	if *cliParams.Bearer_token != "" && (*cliParams.Username != "" || *cliParams.Password != "") {
-		ExitWithError("Cannot provide both bearer_token and basic authentication username/password")
+		return cliParams, errors.New("cannot provide both bearer_token and basic authentication username/password")
+	}
+	if *cliParams.Oauth_token_url != "" && (*cliParams.Bearer_token != "" || *cliParams.Username != "" || *cliParams.Password != "") {
+		return cliParams, errors.New("cannot provide both OAuth client credentials and bearer_token or basic authentication username/password")
+	}
+	if *cliParams.Oauth_token_url != "" && (*cliParams.Oauth_client_id == "" || *cliParams.Oauth_client_secret == "") {
+		return cliParams, errors.New("both oauth_client_id and oauth_client_secret must be provided with oauth_token_url")
 	}
-	if *cliParams.Bearer_token == "" && *cliParams.Username == "" {
-		ExitWithError("Either bearer_token or basic authentication username/password must be provided")
+	cliParams.Client_certificate = p.string("client_certificate", cliParams.Client_certificate, false, "")
+	cliParams.Client_certificate_file = p.string("client_certificate_file", cliParams.Client_certificate_file, false, "")
+	cliParams.Client_private_key = p.string("client_private_key", cliParams.Client_private_key, false, "")
+	cliParams.Client_private_key_file = p.string("client_private_key_file", cliParams.Client_private_key_file, false, "")
+	if *cliParams.Bearer_token == "" && *cliParams.Username == "" && *cliParams.Oauth_token_url == "" && !cliParams.HasClientCertificate() {
+		return cliParams, errors.New("either bearer_token, basic authentication username/password, OAuth client credentials or a client certificate must be provided")
 	}
 	if *cliParams.Username != "" && *cliParams.Password == "" {
-		ExitWithError("Password must be provided when username is provided")
+		return cliParams, errors.New("password must be provided when username is provided")
+	}
+	cliParams.Retries = p.int64("retries", cliParams.Retries, false, semp.DefaultRetries)
+	cliParams.Retry_min_interval = p.duration("retry_min_interval", cliParams.Retry_min_interval, false, semp.DefaultRetryMinInterval)
+	cliParams.Retry_max_interval = p.duration("retry_max_interval", cliParams.Retry_max_interval, false, semp.DefaultRetryMaxInterval)
+	cliParams.Request_timeout_duration = p.duration("request_timeout_duration", cliParams.Request_timeout_duration, false, semp.DefaultRequestTimeout)
+	cliParams.Request_min_interval = p.duration("request_min_interval", cliParams.Request_min_interval, false, semp.DefaultRequestInterval)
+	cliParams.Insecure_skip_verify = p.boolean("insecure_skip_verify", cliParams.Insecure_skip_verify, false, false)
+	cliParams.Ca_certificate = p.string("ca_certificate", cliParams.Ca_certificate, false, "")
+	cliParams.Ca_bundle_file = p.string("ca_bundle_file", cliParams.Ca_bundle_file, false, "")
+	cliParams.Tls_server_name = p.string("tls_server_name", cliParams.Tls_server_name, false, "")
+	cliParams.Skip_api_check = p.boolean("skip_api_check", cliParams.Skip_api_check, false, false)
+	if p.err != nil {
+		return cliParams, p.err
+	}
+	return updateGeneratorCliParamsWithEnv(cliParams)
+}
+
+// UpdateOfflineCliParamsWithEnv complements the params for generating without broker access, such as from a
+// snapshot. The authentication params only determine the provider configuration in the generated file.
+func UpdateOfflineCliParamsWithEnv(cliParams CliParams) (CliParams, error) {
+	p := &paramsWithEnv{}
+	cliParams.Username = p.string("username", cliParams.Username, false, "")
+	cliParams.Bearer_token = p.string("bearer_token", cliParams.Bearer_token, false, "")
+	cliParams.Oauth_token_url = p.string("oauth_token_url", cliParams.Oauth_token_url, false, "")
+	cliParams.Client_certificate = p.string("client_certificate", cliParams.Client_certificate, false, "")
+	cliParams.Client_certificate_file = p.string("client_certificate_file", cliParams.Client_certificate_file, false, "")
+	if p.err != nil {
+		return cliParams, p.err
+	}
+	return updateGeneratorCliParamsWithEnv(cliParams)
+}
+
+// updateGeneratorCliParamsWithEnv complements the params that control the generated configuration
+func updateGeneratorCliParamsWithEnv(cliParams CliParams) (CliParams, error) {
+	p := &paramsWithEnv{}
+	cliParams.Import_blocks = p.boolean("import_blocks", cliParams.Import_blocks, false, false)
+	cliParams.Module = p.boolean("module", cliParams.Module, false, false)
+	cliParams.Module_variables = p.string("module_variables", cliParams.Module_variables, false, "")
+	cliParams.Parallelism = p.int64("parallelism", cliParams.Parallelism, false, 1)
+	cliParams.Output_format = p.string("output_format", cliParams.Output_format, false, "hcl")
+	cliParams.Include_type = StringSliceParamWithEnv("include_type", cliParams.Include_type)
+	cliParams.Exclude_type = StringSliceParamWithEnv("exclude_type", cliParams.Exclude_type)
+	cliParams.Include_name = StringSliceParamWithEnv("include_name", cliParams.Include_name)
+	cliParams.Exclude_name = StringSliceParamWithEnv("exclude_name", cliParams.Exclude_name)
+	if p.err != nil {
+		return cliParams, p.err
+	}
+	if *cliParams.Parallelism < 1 {
+		return cliParams, errors.New("parallelism must be at least 1")
+	}
+	if *cliParams.Module && *cliParams.Import_blocks {
+		return cliParams, errors.New("cannot generate import blocks for a module, Terraform only supports them in the root module")
 	}
-	cliParams.Retries = Int64ParamWithEnv("retries", cliParams.Retries, false, semp.DefaultRetries)
-	cliParams.Retry_min_interval = DurationParamWithEnv("retry_min_interval", cliParams.Retry_min_interval, false, semp.DefaultRetryMinInterval)
-	cliParams.Retry_max_interval = DurationParamWithEnv("retry_max_interval", cliParams.Retry_max_interval, false, semp.DefaultRetryMaxInterval)
-	cliParams.Request_timeout_duration = DurationParamWithEnv("request_timeout_duration", cliParams.Request_timeout_duration, false, semp.DefaultRequestTimeout)
-	cliParams.Request_min_interval = DurationParamWithEnv("request_min_interval", cliParams.Request_min_interval, false, semp.DefaultRequestInterval)
-	cliParams.Insecure_skip_verify = BooleanParamWithEnv("insecure_skip_verify", cliParams.Insecure_skip_verify, false, false)
-	cliParams.Skip_api_check = BooleanParamWithEnv("skip_api_check", cliParams.Skip_api_check, false, false)
-	return cliParams
+	if !*cliParams.Module && *cliParams.Module_variables != "" {
+		return cliParams, errors.New("module variables can only be provided when generating a module")
+	}
+	return cliParams, nil
+}
+
+// HasClientCertificate returns true if any of the client certificate parameters has been provided
+func (cliParams CliParams) HasClientCertificate() bool {
+	return *cliParams.Client_certificate != "" || *cliParams.Client_certificate_file != ""
+}
+
+// paramsWithEnv complements a series of params with environment variables, keeping the first error. On errors, the
+// params are set to their fallback.
+type paramsWithEnv struct {
+	err error
+}
+
+func (p *paramsWithEnv) keep(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+func (p *paramsWithEnv) string(name string, value *string, isMandatory bool, fallback string) *string {
+	result, err := StringParamWithEnv(name, value, isMandatory, fallback)
+	p.keep(err)
+	return result
+}
+
+func (p *paramsWithEnv) int64(name string, value *int64, isMandatory bool, fallback int64) *int64 {
+	result, err := Int64ParamWithEnv(name, value, isMandatory, fallback)
+	p.keep(err)
+	return result
+}
+
+func (p *paramsWithEnv) boolean(name string, value *bool, isMandatory bool, fallback bool) *bool {
+	result, err := BooleanParamWithEnv(name, value, isMandatory, fallback)
+	p.keep(err)
+	return result
+}
+
+func (p *paramsWithEnv) duration(name string, value *time.Duration, isMandatory bool, fallback time.Duration) *time.Duration {
+	result, err := DurationParamWithEnv(name, value, isMandatory, fallback)
+	p.keep(err)
+	return result
 }
 
-func StringParamWithEnv(name string, value *string, isMandatory bool, fallback string) *string {
+// StringParamWithEnv returns the value of a param, or of its environment variable if not provided, or the fallback.
+// Fails if a mandatory param is missing, returning the fallback with the error.
+func StringParamWithEnv(name string, value *string, isMandatory bool, fallback string) (*string, error) {
 	if value != nil {
-		return value
+		return value, nil
 	}
 	envValue := os.Getenv("SOLACEBROKER_" + strings.ToUpper(name))
 	if len(envValue) == 0 {
 		if isMandatory {
-			ExitWithError(fmt.Sprintf("Required parameter '%s' is missing", name))
-		} else {
-			return &fallback //default to fallback
+			return &fallback, fmt.Errorf("required parameter '%s' is missing", name)
 		}
+		return &fallback, nil //default to fallback
 	}
-	return &envValue
+	return &envValue, nil
 }
 
-func Int64ParamWithEnv(name string, value *int64, isMandatory bool, fallback int64) *int64 {
+// StringSliceParamWithEnv returns the values of a repeatable parameter, or the comma-separated values of its
+// environment variable if not provided
+func StringSliceParamWithEnv(name string, value *[]string) *[]string {
 	if value != nil {
 		return value
 	}
+	values := []string{}
+	envValue := os.Getenv("SOLACEBROKER_" + strings.ToUpper(name))
+	for _, v := range strings.Split(envValue, ",") {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return &values
+}
+
+// Int64ParamWithEnv is StringParamWithEnv for integer params, also failing on invalid environment values
+func Int64ParamWithEnv(name string, value *int64, isMandatory bool, fallback int64) (*int64, error) {
+	if value != nil {
+		return value, nil
+	}
 	envValue := os.Getenv("SOLACEBROKER_" + strings.ToUpper(name))
 	if len(envValue) == 0 {
 		if isMandatory {
-			ExitWithError(fmt.Sprintf("Required parameter '%s' is missing", name))
-		} else {
-			return &fallback //default to fallback
+			return &fallback, fmt.Errorf("required parameter '%s' is missing", name)
 		}
+		return &fallback, nil //default to fallback
 	}
 	i, err := strconv.ParseInt(envValue, 10, 64)
 	if err != nil {
-		ExitWithError(fmt.Sprintf("Invalid value for %s: %s", name, envValue))
+		return &fallback, fmt.Errorf("invalid value for %s: %s", name, envValue)
 	}
-	return &i
+	return &i, nil
 }
 
-func BooleanParamWithEnv(name string, value *bool, isMandatory bool, fallback bool) *bool {
+// BooleanParamWithEnv is StringParamWithEnv for boolean params, also failing on invalid environment values
+func BooleanParamWithEnv(name string, value *bool, isMandatory bool, fallback bool) (*bool, error) {
 	if value != nil {
-		return value
+		return value, nil
 	}
 	envValue := os.Getenv("SOLACEBROKER_" + strings.ToUpper(name))
 	if len(envValue) == 0 {
 		if isMandatory {
-			ExitWithError(fmt.Sprintf("Required parameter '%s' is missing", name))
-		} else {
-			return &fallback //default to fallback
+			return &fallback, fmt.Errorf("required parameter '%s' is missing", name)
 		}
+		return &fallback, nil //default to fallback
 	}
 	b, err := strconv.ParseBool(envValue)
 	if err != nil {
-		ExitWithError(fmt.Sprintf("Invalid value for %s: %s", name, envValue))
+		return &fallback, fmt.Errorf("invalid value for %s: %s", name, envValue)
 	}
-	return &b
+	return &b, nil
 }
 
-func DurationParamWithEnv(name string, value *time.Duration, isMandatory bool, fallback time.Duration) *time.Duration {
+// DurationParamWithEnv is StringParamWithEnv for duration params, also failing on invalid environment values
+func DurationParamWithEnv(name string, value *time.Duration, isMandatory bool, fallback time.Duration) (*time.Duration, error) {
 	if value != nil {
-		return value
+		return value, nil
 	}
 	envValue := os.Getenv("SOLACEBROKER_" + strings.ToUpper(name))
 	if len(envValue) == 0 {
 		if isMandatory {
-			ExitWithError(fmt.Sprintf("Required parameter '%s' is missing", name))
-		} else {
-			return &fallback //default to fallback
+			return &fallback, fmt.Errorf("required parameter '%s' is missing", name)
 		}
+		return &fallback, nil //default to fallback
 	}
 	d, err := time.ParseDuration(envValue)
 	if err != nil {
-		ExitWithError(fmt.Sprintf("Invalid value for %s: %s", name, envValue))
+		return &fallback, fmt.Errorf("invalid value for %s: %s", name, envValue)
 	}
-	return &d
+	return &d, nil
 }
 
-// Workaround filter for system provisioned attributes
-func isSystemProvisionedAttribute(attribute string) bool {
-	systemProvisioned := strings.HasPrefix(attribute, "#") && attribute != "#DEAD_MSG_QUEUE"
-	return systemProvisioned
+// IsDirectoryOutput returns true if the generated configuration is to be written to a directory rather than a single
+// file: when the name ends with a path separator or is an existing directory
+func IsDirectoryOutput(fileName string) bool {
+	if strings.HasSuffix(fileName, "/") || strings.HasSuffix(fileName, string(os.PathSeparator)) {
+		return true
+	}
+	info, err := os.Stat(fileName)
+	return err == nil && info.IsDir()
 }
 
+// LogWriter receives the messages of the generator, commands writing results to stdout log to stderr instead
+var LogWriter io.Writer = os.Stdout
+
 func LogCLIError(err string) {
-	_, _ = fmt.Fprintf(os.Stdout, "%s %s %s\n", Red, err, Reset)
+	_, _ = fmt.Fprintf(LogWriter, "%s %s %s\n", Red, err, Reset)
 }
 
 func LogCLIInfo(info string) {
-	_, _ = fmt.Fprintf(os.Stdout, "\n%s %s %s", Reset, info, Reset)
+	logInfo(LogWriter, info)
+}
+
+func logInfo(writer io.Writer, info string) {
+	_, _ = fmt.Fprintf(writer, "\n%s %s %s", Reset, info, Reset)
 }
 
 func ExitWithError(err string) {
@@ -209,46 +366,39 @@ func ConvertAttributeTextToMap(attribute string) map[string]string {
 	return attributeMap
 }
 
-func resourcesToFormattedHCL(brokerResources []map[string]ResourceConfig) []map[string]string {
+func resourcesToFormattedHCL(brokerResources []map[string]ResourceConfig) ([]map[string]string, error) {
 	var formattedResult []map[string]string
 	for _, resources := range brokerResources {
 		resourceCollection := make(map[string]string)
 		for resourceTypeAndName := range resources {
-			formattedResource := hclFormatResource(resources[resourceTypeAndName])
+			formattedResource, err := hclFormatResource(resourceTypeAndName, resources[resourceTypeAndName])
+			if err != nil {
+				return nil, err
+			}
 			resourceCollection[resourceTypeAndName] = formattedResource
 		}
 		formattedResult = append(formattedResult, resourceCollection)
 	}
-	return formattedResult
+	return formattedResult, nil
 }
 
-func hclFormatResource(resourceConfig ResourceConfig) string {
-	var attributeNames []string
-	for attributeName := range resourceConfig.ResourceAttributes {
-		attributeNames = append(attributeNames, attributeName)
-	}
-	sort.Strings(attributeNames)
-	var b bytes.Buffer
-	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
-	for pos := range attributeNames {
-		attributeName := attributeNames[pos]
-		attributeConfigLine := "\t" + attributeName + "\t" + "= "
-		attributeConfigLine += resourceConfig.ResourceAttributes[attributeName].AttributeValue
-		attributeConfigLine += resourceConfig.ResourceAttributes[attributeName].Comment
-		fmt.Fprintln(w, attributeConfigLine)
+// resourcesToImportIds returns the HCL quoted import identifiers of the resources, omitting singletons which have none
+func resourcesToImportIds(brokerResources []map[string]ResourceConfig) map[string]string {
+	importIds := map[string]string{}
+	for _, resources := range brokerResources {
+		for resourceTypeAndName, resourceConfig := range resources {
+			if resourceConfig.ImportId != "" {
+				importIds[resourceTypeAndName] = "\"" + SanitizeHclStringValue(resourceConfig.ImportId) + "\""
+			}
+		}
 	}
-	w.Flush()
-	config := b.String()
-	return config
+	return importIds
 }
 
+// SanitizeHclStringValue returns the value quoted for an HCL string literal, without the enclosing quotes
 func SanitizeHclStringValue(value string) string {
-	b, _ := json.Marshal(value)
-	s := string(b)
-	output := s[1 : len(s)-1]
-	output = strings.ReplaceAll(output, "${", "$${")
-	output = strings.ReplaceAll(output, "%{", "%%{")
-	return output
+	quoted := string(hclwrite.TokensForValue(cty.StringVal(value)).Bytes())
+	return quoted[1 : len(quoted)-1]
 }
 
 func isStartRune(r rune) bool {
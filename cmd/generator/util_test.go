@@ -18,6 +18,7 @@ package generator
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 // Add unit test for CliParamsWithEnv
@@ -52,11 +53,32 @@ func TestCliParamsWithEnv(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			UpdateCliParamsWithEnv(tt.args.cliParams)
+			if _, err := UpdateCliParamsWithEnv(tt.args.cliParams); err != nil {
+				t.Errorf("UpdateCliParamsWithEnv() error = %v", err)
+			}
 		})
 	}
 }
 
+func TestCliParamsWithEnvErrors(t *testing.T) {
+	t.Setenv("SOLACEBROKER_URL", "")
+	if _, err := UpdateCliParamsWithEnv(CliParams{}); err == nil {
+		t.Errorf("UpdateCliParamsWithEnv() without url, expected error")
+	}
+	t.Setenv("SOLACEBROKER_RETRIES", "many")
+	if value, err := Int64ParamWithEnv("retries", nil, false, 10); err == nil || *value != 10 {
+		t.Errorf("Int64ParamWithEnv() = %v, %v, expected fallback and error", *value, err)
+	}
+	t.Setenv("SOLACEBROKER_INSECURE_SKIP_VERIFY", "maybe")
+	if _, err := BooleanParamWithEnv("insecure_skip_verify", nil, false, false); err == nil {
+		t.Errorf("BooleanParamWithEnv() expected error")
+	}
+	t.Setenv("SOLACEBROKER_REQUEST_TIMEOUT_DURATION", "1 minute")
+	if _, err := DurationParamWithEnv("request_timeout_duration", nil, false, time.Minute); err == nil {
+		t.Errorf("DurationParamWithEnv() expected error")
+	}
+}
+
 func Test_newAttributeInfo(t *testing.T) {
 	type args struct {
 		value string
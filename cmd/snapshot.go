@@ -0,0 +1,94 @@
+// Package cmd terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"terraform-provider-solacebroker/cmd/generator"
+
+	"github.com/spf13/cobra"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot <resource type> <provider-specific identifier> <filename>",
+	Short: "Saves the raw configuration of a specified PubSub+ event broker object and all child objects to a snapshot file",
+	Long: `The snapshot command on the provider binary saves the raw SEMP configuration of the specified object and all child objects known to the provider to a file.
+This is not a Terraform command. The snapshot can be used later to generate a Terraform configuration without access to the broker, using "generate --from_snapshot".
+
+  <binary> snapshot [flags] <resource type> <provider-specific identifier> <filename>
+
+  where:
+		<binary> is the broker provider binary
+		[flags] are the supported options, which mirror the configuration options for the provider object (for example --url=https://localhost:1943 and --retry_wait_max=90s) and can also be set via environment variables in the same way.
+		<resource type> the resource type of the specified object, for example solacebroker_msg_vpn
+		<provider-specific identifier> the import identifier of the specified object instance, refer to the resource type of the object in the provider documentation
+		<filename> is the name of the snapshot file, which is compressed with gzip if it ends with ".gz"
+
+Example:
+  SOLACEBROKER_USERNAME=adminuser SOLACEBROKER_PASSWORD=pass \
+	terraform-provider-solacebroker snapshot --url=http://localhost:8080 solacebroker_msg_vpn test vpn-snapshot.json.gz
+
+This command will create a file vpn-snapshot.json.gz that contains the configuration of the 'test' message VPN and any child objects on the broker, including system provisioned objects.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 3 {
+			// Print the help message if the required arguments are not provided
+			_ = cmd.Help()
+			os.Exit(1)
+		}
+		flags := cmd.Flags()
+		cliParams, err := generator.UpdateCliParamsWithEnv(cliParamsFromFlags(flags))
+		if err != nil {
+			generator.ExitWithError("\nError: " + err.Error() + "\n\n")
+		}
+
+		brokerObjectType := strings.TrimPrefix(flags.Arg(0), "solacebroker_")
+		providerSpecificIdentifier := flags.Arg(1)
+		fileName := flags.Arg(2)
+		if len(brokerObjectType) == 0 || len(fileName) == 0 {
+			_ = cmd.Help()
+			os.Exit(1)
+		}
+
+		if !generator.IsBrokerObjectType(generator.BrokerObjectType(brokerObjectType)) {
+			generator.ExitWithError("\nError: Broker resource not found by terraform name : " + brokerObjectType + "\n\n")
+		}
+
+		cliClient, about := connectToBroker(cmd, cliParams)
+
+		generator.LogCLIInfo(fmt.Sprintf("Attempting snapshot for object and its child-objects: %s, identifier: %s, destination file: %s\n", brokerObjectType, providerSpecificIdentifier, fileName))
+		snapshot, err := generator.TakeSnapshot(cmd.Context(), cliClient, generator.BrokerObjectType(brokerObjectType), providerSpecificIdentifier, about, int(*cliParams.Parallelism), generator.LogWriter)
+		if err != nil {
+			generator.ExitWithError("Failed to fetch broker config, " + err.Error())
+		}
+		if err := generator.WriteSnapshot(snapshot, fileName); err != nil {
+			generator.ExitWithError("Failed to write file, " + err.Error())
+		}
+		generator.LogCLIInfo(fileName + " created successfully.\n")
+
+		os.Exit(0)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+	snapshotCmd.PersistentFlags().String("url", "http://localhost:8080", "Broker base URL, for example https://mybroker.example.org:<semp-service-port>")
+	addConnectionFlags(snapshotCmd)
+	snapshotCmd.PersistentFlags().Int64("parallelism", 1, "Maximum number of concurrent SEMP requests to fetch child objects")
+}
@@ -49,11 +49,14 @@ type AttributeInfo struct {
 	Identifying         bool
 	Required            bool
 	Sensitive           bool
+	WriteOnly           bool // the value is only taken from the configuration and never stored in the state
+	WriteOnlyVersion    bool // the version of a write-only attribute, only stored in the state and not sent to the broker
 	ReadOnly            bool
 	RequiresReplace     bool
 	Deprecated          bool
 	Requires            []string
 	ConflictsWith       []string
+	RequiresDisabled    []string
 	Type                attr.Type
 	TerraformType       tftypes.Type
 	Attributes          []*AttributeInfo
@@ -62,4 +65,11 @@ type AttributeInfo struct {
 	Int64Validators     []validator.Int64
 	BoolValidators      []validator.Bool
 	Default             any
+	References          *AttributeReference
+}
+
+// AttributeReference describes the broker object named by the value of an attribute
+type AttributeReference struct {
+	TerraformName string // the resource type of the referenced object
+	Attribute     string // the identifying attribute of the referenced object that holds the value
 }
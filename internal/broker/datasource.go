@@ -62,7 +62,7 @@ func (ds *brokerDataSource) Configure(_ context.Context, request datasource.Conf
 	if request.ProviderData == nil {
 		return
 	}
-	client, ok := request.ProviderData.(*semp.Client)
+	client, ok := request.ProviderData.(*brokerClient)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected datasource configuration",
@@ -75,7 +75,7 @@ func (ds *brokerDataSource) Configure(_ context.Context, request datasource.Conf
 
 func (ds *brokerDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
 	client := ds.client
-	if err := checkBrokerRequirements(ctx, client); err != nil {
+	if err := client.checkBrokerRequirements(ctx); err != nil {
 		addErrorToDiagnostics(&response.Diagnostics, "Broker check failed", err)
 		return
 	}
@@ -87,9 +87,9 @@ func (ds *brokerDataSource) Read(ctx context.Context, request datasource.ReadReq
 	sempData, err := client.RequestWithoutBody(ctx, http.MethodGet, sempPath)
 	if err != nil {
 		if errors.Is(err, semp.ErrResourceNotFound) {
-			addErrorToDiagnostics(&response.Diagnostics, fmt.Sprintf("Detected missing data source %v", sempPath), errors.Unwrap(err))
+			addErrorToDiagnostics(&response.Diagnostics, fmt.Sprintf("Detected missing data source %v", sempPath), err)
 		} else {
-			addErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err)
+			addSempErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err, nil)
 		}
 		return
 	}
@@ -103,5 +103,7 @@ func (ds *brokerDataSource) Read(ctx context.Context, request datasource.ReadReq
 }
 
 func (ds *brokerDataSource) ConfigValidators(_ context.Context) []datasource.ConfigValidator {
-	return nil
+	return []datasource.ConfigValidator{
+		attributeRelationshipValidator{attributes: ds.attributes},
+	}
 }
@@ -0,0 +1,138 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+type sempErrorHint struct {
+	summary string
+	hint    string
+}
+
+// Actionable summaries for well-known SEMP error statuses
+var sempErrorHints = map[string]sempErrorHint{
+	"ALREADY_EXISTS": {
+		"object already exists",
+		"The object already exists on the broker. Import it into the Terraform state using \"terraform import\" or an import block instead of creating it, or remove it from the broker.",
+	},
+	"NOT_ALLOWED": {
+		"operation not allowed",
+		"The broker does not allow this change in the current state of the object. Some attributes can only be changed while the object is disabled, or only when the object is created; disable the object first or plan a replacement.",
+	},
+	"INVALID_PARAMETER": {
+		"invalid attribute value",
+		"The broker rejected an attribute value. Check the value against the attribute documentation and the limits configured on the broker.",
+	},
+	"MISSING_PARAMETER": {
+		"missing attribute",
+		"The broker requires an attribute that has not been configured. Check the attribute dependencies in the documentation.",
+	},
+	"NOT_SUPPORTED": {
+		"not supported by the broker",
+		"The broker does not support this object or attribute. Check that the broker version supports it, or remove it from the configuration.",
+	},
+	"NOT_FOUND": {
+		"object not found",
+		"The object or one of its parent objects does not exist on the broker. Check that the parent objects have been created, or refresh the Terraform state.",
+	},
+	"INVALID_PATH": {
+		"unknown object type",
+		"The broker does not know this object type. Check that the broker version supports it.",
+	},
+}
+
+// Actionable summaries for HTTP status codes reported without a SEMP error status
+var httpErrorHints = map[int]sempErrorHint{
+	http.StatusUnauthorized: {
+		"authentication failed",
+		"The broker rejected the provider credentials. Check the username and password, bearer token, OAuth client credentials or client certificate configured for the provider.",
+	},
+	http.StatusForbidden: {
+		"permission denied",
+		"The provider credentials are not authorized for this operation. Check the access level of the management user on the broker.",
+	},
+}
+
+// addSempErrorToDiagnostics adds a failed SEMP request to the diagnostics. Well-known SEMP errors get an actionable
+// summary and hint. If the broker's error description names exactly one of the attributes, the diagnostic points at
+// the attribute path.
+func addSempErrorToDiagnostics(diags *diag.Diagnostics, summary string, err error, attributes []*AttributeInfo) {
+	var sempError *semp.Error
+	if !errors.As(err, &sempError) {
+		addErrorToDiagnostics(diags, summary, err)
+		return
+	}
+	detail := err.Error()
+	hint, ok := sempErrorHints[sempError.Status]
+	if !ok {
+		hint, ok = httpErrorHints[sempError.HTTPStatus]
+	}
+	if ok {
+		summary = summary + ": " + hint.summary
+		detail = detail + "\n\n" + hint.hint
+	}
+	if attributePath, found := attributePathFromWords(attributes, descriptionWords(sempError.Description), path.Empty()); found {
+		diags.AddAttributeError(attributePath, summary, detail)
+		return
+	}
+	diags.AddError(summary, detail)
+}
+
+// descriptionWords returns the set of words in a SEMP error description
+func descriptionWords(description string) map[string]bool {
+	words := map[string]bool{}
+	for _, word := range strings.FieldsFunc(description, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) {
+		words[word] = true
+	}
+	return words
+}
+
+// attributePathFromWords finds the attribute whose SEMP name is one of the words of a SEMP error description. The
+// path is only returned if exactly one attribute is named, as short names such as "enabled" may also be ordinary
+// words. Nested attributes are only considered if their parent is named as well.
+func attributePathFromWords(attributes []*AttributeInfo, words map[string]bool, parent path.Path) (path.Path, bool) {
+	var named *AttributeInfo
+	for _, attr := range attributes {
+		if !words[attr.SempName] {
+			continue
+		}
+		if named != nil {
+			return path.Empty(), false
+		}
+		named = attr
+	}
+	if named == nil {
+		return path.Empty(), false
+	}
+	attributePath := parent.AtName(named.TerraformName)
+	if nestedPath, found := attributePathFromWords(named.Attributes, words, attributePath); found {
+		return nestedPath, true
+	}
+	return attributePath, true
+}
@@ -0,0 +1,124 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func TestAddSempErrorToDiagnostics(t *testing.T) {
+	attributes := []*AttributeInfo{
+		{SempName: "queueName", TerraformName: "queue_name"},
+		{SempName: "maxMsgSize", TerraformName: "max_msg_size"},
+		{SempName: "enabled", TerraformName: "enabled"},
+		{SempName: "eventBindCountThreshold", TerraformName: "event_bind_count_threshold", Attributes: []*AttributeInfo{
+			{SempName: "clearPercent", TerraformName: "clear_percent"},
+		}},
+	}
+	tests := []struct {
+		name        string
+		err         error
+		wantSummary string
+		wantPath    path.Path
+	}{
+		{
+			"InvalidParameter",
+			&semp.Error{HTTPStatus: http.StatusBadRequest, Status: "INVALID_PARAMETER", Description: "Problem with maxMsgSize: value out of range"},
+			"SEMP call failed: invalid attribute value",
+			path.Root("max_msg_size"),
+		},
+		{
+			"NotAllowedNested",
+			&semp.Error{HTTPStatus: http.StatusBadRequest, Status: "NOT_ALLOWED", Description: "eventBindCountThreshold clearPercent cannot be changed"},
+			"SEMP call failed: operation not allowed",
+			path.Root("event_bind_count_threshold").AtName("clear_percent"),
+		},
+		{
+			"SeveralAttributesNamed",
+			&semp.Error{HTTPStatus: http.StatusBadRequest, Status: "NOT_ALLOWED", Description: "maxMsgSize cannot be changed while enabled"},
+			"SEMP call failed: operation not allowed",
+			path.Empty(),
+		},
+		{
+			"TerraformNameNotMatched",
+			&semp.Error{HTTPStatus: http.StatusBadRequest, Status: "INVALID_PARAMETER", Description: "Problem with max_msg_size"},
+			"SEMP call failed: invalid attribute value",
+			path.Empty(),
+		},
+		{
+			"NoAttributeNamed",
+			&semp.Error{HTTPStatus: http.StatusBadRequest, Status: "ALREADY_EXISTS", Description: "Object already exists"},
+			"SEMP call failed: object already exists",
+			path.Empty(),
+		},
+		{
+			"WordBoundary",
+			&semp.Error{HTTPStatus: http.StatusBadRequest, Status: "NOT_ALLOWED", Description: "egressEnabled cannot be changed"},
+			"SEMP call failed: operation not allowed",
+			path.Empty(),
+		},
+		{
+			"HTTPStatus",
+			&semp.Error{HTTPStatus: http.StatusUnauthorized, Description: "denied"},
+			"SEMP call failed: authentication failed",
+			path.Empty(),
+		},
+		{
+			"Wrapped",
+			fmt.Errorf("wrapped: %w", &semp.Error{HTTPStatus: http.StatusBadRequest, Status: "SOME_OTHER", Description: "queueName is bad"}),
+			"SEMP call failed",
+			path.Root("queue_name"),
+		},
+		{
+			"NotSempError",
+			errors.New("connection refused"),
+			"SEMP call failed",
+			path.Empty(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			addSempErrorToDiagnostics(&diags, "SEMP call failed", tt.err, attributes)
+			if len(diags) != 1 {
+				t.Fatalf("addSempErrorToDiagnostics() returned %d diagnostics, want 1: %v", len(diags), diags)
+			}
+			if diags[0].Summary() != tt.wantSummary {
+				t.Errorf("summary = %q, want %q", diags[0].Summary(), tt.wantSummary)
+			}
+			if !strings.Contains(diags[0].Detail(), tt.err.Error()) {
+				t.Errorf("detail = %q, want it to contain %q", diags[0].Detail(), tt.err.Error())
+			}
+			gotPath := path.Empty()
+			if withPath, ok := diags[0].(diag.DiagnosticWithPath); ok {
+				gotPath = withPath.Path()
+			}
+			if !gotPath.Equal(tt.wantPath) {
+				t.Errorf("path = %v, want %v", gotPath, tt.wantPath)
+			}
+		})
+	}
+}
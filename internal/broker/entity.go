@@ -19,7 +19,6 @@ package broker
 import (
 	"fmt"
 	"reflect"
-	"terraform-provider-solacebroker/internal/semp"
 
 	dschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -45,7 +44,7 @@ type brokerEntityBase struct {
 	identifyingAttributes []*AttributeInfo
 	attributes            []*AttributeInfo
 	converter             *ObjectConverter
-	client                *semp.Client
+	client                *brokerClient
 }
 
 func copyMatchingFields(prefix string, in reflect.Value, out reflect.Value) {
@@ -0,0 +1,200 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ function.Function = &topicMatchesFunction{}
+	_ function.Function = &validateTopicFunction{}
+	_ function.Function = &isSharedSubscriptionFunction{}
+	_ function.Function = &parseSharedSubscriptionFunction{}
+)
+
+var Functions = []func() function.Function{
+	func() function.Function { return &topicMatchesFunction{} },
+	func() function.Function { return &validateTopicFunction{} },
+	func() function.Function { return &isSharedSubscriptionFunction{} },
+	func() function.Function { return &parseSharedSubscriptionFunction{} },
+}
+
+const subscriptionParameterDescription = "A topic subscription, optionally prefixed by `#noexport/` and `#share/<share name>/`."
+
+// topicMatchesFunction implements topic_matches(subscription, topic)
+type topicMatchesFunction struct{}
+
+func (f *topicMatchesFunction) Metadata(_ context.Context, _ function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "topic_matches"
+}
+
+func (f *topicMatchesFunction) Definition(_ context.Context, _ function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Summary:             "Checks whether a topic subscription matches a topic",
+		MarkdownDescription: "Returns true if the topic subscription matches the topic, following the wildcard rules of Solace Message Format (SMF) topics: a level `*` matches any single level, a level ending in `*`, for example `ord*`, matches any level starting with the characters before the `*`, and `>` as the last level matches one or more levels. Elsewhere `*` and `>` are literal characters. The `#noexport/` and `#share/<share name>/` prefixes of a subscription are not part of the matched topic. Fails if the subscription or the topic is not valid.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "subscription",
+				MarkdownDescription: subscriptionParameterDescription,
+			},
+			function.StringParameter{
+				Name:                "topic",
+				MarkdownDescription: "The topic a message is published to.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *topicMatchesFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var subscription, topic string
+	response.Error = request.Arguments.Get(ctx, &subscription, &topic)
+	if response.Error != nil {
+		return
+	}
+	parsed, err := parseSubscription(subscription)
+	if err != nil {
+		response.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	if err := validateTopic(topic); err != nil {
+		response.Error = function.NewArgumentFuncError(1, err.Error())
+		return
+	}
+	response.Error = response.Result.Set(ctx, topicMatches(parsed.topic, topic))
+}
+
+// validateTopicFunction implements validate_topic(topic)
+type validateTopicFunction struct{}
+
+func (f *validateTopicFunction) Metadata(_ context.Context, _ function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "validate_topic"
+}
+
+func (f *validateTopicFunction) Definition(_ context.Context, _ function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Summary:             "Checks the syntax of a topic or topic subscription",
+		MarkdownDescription: fmt.Sprintf("Returns true if the topic or topic subscription is valid: between 1 and %d bytes of UTF-8 without null characters, in levels separated by `/` that must not be empty.", maxTopicLength),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "topic",
+				MarkdownDescription: "The topic or topic subscription to check.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *validateTopicFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var topic string
+	response.Error = request.Arguments.Get(ctx, &topic)
+	if response.Error != nil {
+		return
+	}
+	response.Error = response.Result.Set(ctx, validateTopic(topic) == nil)
+}
+
+// isSharedSubscriptionFunction implements is_shared_subscription(subscription)
+type isSharedSubscriptionFunction struct{}
+
+func (f *isSharedSubscriptionFunction) Metadata(_ context.Context, _ function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "is_shared_subscription"
+}
+
+func (f *isSharedSubscriptionFunction) Definition(_ context.Context, _ function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Summary:             "Checks whether a topic subscription is a shared subscription",
+		MarkdownDescription: "Returns true if the topic subscription starts with `#share/`, optionally preceded by `#noexport/`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "subscription",
+				MarkdownDescription: subscriptionParameterDescription,
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *isSharedSubscriptionFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var subscription string
+	response.Error = request.Arguments.Get(ctx, &subscription)
+	if response.Error != nil {
+		return
+	}
+	response.Error = response.Result.Set(ctx, isSharedSubscription(subscription))
+}
+
+// parseSharedSubscriptionFunction implements parse_shared_subscription(subscription)
+type parseSharedSubscriptionFunction struct{}
+
+type sharedSubscriptionData struct {
+	ShareName types.String `tfsdk:"share_name"`
+	Topic     types.String `tfsdk:"topic"`
+	NoExport  types.Bool   `tfsdk:"no_export"`
+}
+
+func (f *parseSharedSubscriptionFunction) Metadata(_ context.Context, _ function.MetadataRequest, response *function.MetadataResponse) {
+	response.Name = "parse_shared_subscription"
+}
+
+func (f *parseSharedSubscriptionFunction) Definition(_ context.Context, _ function.DefinitionRequest, response *function.DefinitionResponse) {
+	response.Definition = function.Definition{
+		Summary:             "Splits a shared subscription into its share name and topic",
+		MarkdownDescription: "Returns an object with the `share_name` and the `topic` of a shared subscription of the form `#share/<share name>/<topic>`, and `no_export` set to true if the subscription is prefixed by `#noexport/`. Fails if the subscription is not a valid shared subscription.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "subscription",
+				MarkdownDescription: "A shared subscription, optionally prefixed by `#noexport/`.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: map[string]attr.Type{
+				"share_name": types.StringType,
+				"topic":      types.StringType,
+				"no_export":  types.BoolType,
+			},
+		},
+	}
+}
+
+func (f *parseSharedSubscriptionFunction) Run(ctx context.Context, request function.RunRequest, response *function.RunResponse) {
+	var subscription string
+	response.Error = request.Arguments.Get(ctx, &subscription)
+	if response.Error != nil {
+		return
+	}
+	if !isSharedSubscription(subscription) {
+		response.Error = function.NewArgumentFuncError(0, fmt.Sprintf("subscription %q is not a shared subscription", subscription))
+		return
+	}
+	parsed, err := parseSubscription(subscription)
+	if err != nil {
+		response.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+	response.Error = response.Result.Set(ctx, sharedSubscriptionData{
+		ShareName: types.StringValue(parsed.shareName),
+		Topic:     types.StringValue(parsed.topic),
+		NoExport:  types.BoolValue(parsed.noExport),
+	})
+}
@@ -0,0 +1,69 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func runFunction(t *testing.T, f function.Function, arguments ...string) (attr.Value, *function.FuncError) {
+	t.Helper()
+	ctx := context.Background()
+	definition := &function.DefinitionResponse{}
+	f.Definition(ctx, function.DefinitionRequest{}, definition)
+	var values []attr.Value
+	for _, argument := range arguments {
+		values = append(values, types.StringValue(argument))
+	}
+	response := &function.RunResponse{Result: function.NewResultData(definition.Definition.Return.GetType().ValueType(ctx))}
+	f.Run(ctx, function.RunRequest{Arguments: function.NewArgumentsData(values)}, response)
+	return response.Result.Value(), response.Error
+}
+
+func TestFunctions(t *testing.T) {
+	if got, err := runFunction(t, &topicMatchesFunction{}, "#share/group/a/*/c/>", "a/b/c/d"); err != nil || !got.Equal(types.BoolValue(true)) {
+		t.Errorf("topic_matches() = %v, %v, want true", got, err)
+	}
+	if _, err := runFunction(t, &topicMatchesFunction{}, "a//b", "a/b"); err == nil || *err.FunctionArgument != 0 {
+		t.Errorf("topic_matches() with an invalid subscription = %v, want an error for the subscription", err)
+	}
+	if _, err := runFunction(t, &topicMatchesFunction{}, "a/>", "a/"); err == nil || *err.FunctionArgument != 1 {
+		t.Errorf("topic_matches() with an invalid topic = %v, want an error for the topic", err)
+	}
+	if got, err := runFunction(t, &validateTopicFunction{}, "a//b"); err != nil || !got.Equal(types.BoolValue(false)) {
+		t.Errorf("validate_topic() = %v, %v, want false", got, err)
+	}
+	if got, err := runFunction(t, &isSharedSubscriptionFunction{}, "#noexport/#share/group/a"); err != nil || !got.Equal(types.BoolValue(true)) {
+		t.Errorf("is_shared_subscription() = %v, %v, want true", got, err)
+	}
+
+	got, err := runFunction(t, &parseSharedSubscriptionFunction{}, "#noexport/#share/group/a/>")
+	want := types.ObjectValueMust(
+		map[string]attr.Type{"share_name": types.StringType, "topic": types.StringType, "no_export": types.BoolType},
+		map[string]attr.Value{"share_name": types.StringValue("group"), "topic": types.StringValue("a/>"), "no_export": types.BoolValue(true)},
+	)
+	if err != nil || !got.Equal(want) {
+		t.Errorf("parse_shared_subscription() = %v, %v, want %v", got, err, want)
+	}
+	if _, err := runFunction(t, &parseSharedSubscriptionFunction{}, "a/>"); err == nil {
+		t.Errorf("parse_shared_subscription() of a subscription that is not shared succeeded, want an error")
+	}
+}
@@ -0,0 +1,367 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+// The operators of SEMP where conditions, longest first so that "<=" is not taken for "<"
+var whereOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// brokerListDataSource lists the objects of a collection, such as the queues of a message VPN
+type brokerListDataSource struct {
+	schema           schema.Schema
+	terraformName    string
+	pathTemplate     string           // the path of the collection
+	parentAttributes []*AttributeInfo // the identifying attributes of the parent object, in the collection path
+	itemAttributes   []*AttributeInfo // the attributes of the listed objects, as in the data source of one object
+	itemsName        string
+	itemConverter    *ObjectConverter
+	client           *brokerClient
+}
+
+var (
+	_ datasource.DataSourceWithConfigure = &brokerListDataSource{}
+)
+
+// collectionPathTemplate returns the path of the collection of an object, if the object is part of one
+func collectionPathTemplate(pathTemplate string) (string, bool) {
+	// Example path template: /msgVpns/{msgVpnName}/queues/{queueName}
+	// Example collection path template: /msgVpns/{msgVpnName}/queues
+	i := strings.LastIndex(pathTemplate, "/")
+	if i <= 0 || !strings.Contains(pathTemplate[i:], "{") {
+		return "", false
+	}
+	return pathTemplate[:i], true
+}
+
+// pluralTerraformName returns the name of the list data source of an object type, following the naming of the SEMP
+// collections, for example msg_vpn_queues, msg_vpn_proxies or dmr_cluster_link_remote_addresses
+func pluralTerraformName(terraformName string) string {
+	switch {
+	case strings.HasSuffix(terraformName, "y"):
+		return strings.TrimSuffix(terraformName, "y") + "ies"
+	case strings.HasSuffix(terraformName, "s"), strings.HasSuffix(terraformName, "x"):
+		return terraformName + "es"
+	}
+	return terraformName + "s"
+}
+
+// snakeCase converts a SEMP name to the corresponding Terraform name, for example queueTemplates to queue_templates
+func snakeCase(sempName string) string {
+	var sb strings.Builder
+	for _, r := range sempName {
+		if unicode.IsUpper(r) {
+			sb.WriteByte('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// readableAttributes returns the attributes that can be read from the broker, without the write-only ones
+func readableAttributes(attributes []*AttributeInfo) []*AttributeInfo {
+	var result []*AttributeInfo
+	for _, attr := range attributes {
+		if attr.Sensitive {
+			continue
+		}
+		if attr.Attributes != nil {
+			nested := *attr
+			nested.Attributes = readableAttributes(attr.Attributes)
+			nested.TerraformType = tftypes.Object{AttributeTypes: terraformTypes(nested.Attributes)}
+			nested.Converter = NewObjectConverter(attr.TerraformName, nested.Attributes)
+			attr = &nested
+		}
+		result = append(result, attr)
+	}
+	return result
+}
+
+// computedAttribute returns an attribute of the data source of one object as an attribute of a listed object
+func computedAttribute(attribute schema.Attribute) schema.Attribute {
+	switch a := attribute.(type) {
+	case schema.StringAttribute:
+		a.Required, a.Computed, a.Validators = false, true, nil
+		return a
+	case schema.Int64Attribute:
+		a.Required, a.Computed, a.Validators = false, true, nil
+		return a
+	case schema.BoolAttribute:
+		a.Required, a.Computed, a.Validators = false, true, nil
+		return a
+	case schema.SingleNestedAttribute:
+		a.Required, a.Computed = false, true
+		return a
+	}
+	return attribute
+}
+
+func newBrokerListDataSource(inputs EntityInputs) (brokerListDataSource, bool) {
+	collectionPath, ok := collectionPathTemplate(inputs.PathTemplate)
+	if !ok {
+		return brokerListDataSource{}, false
+	}
+	entity := newBrokerDataSource(inputs)
+	ds := brokerListDataSource{
+		terraformName:  pluralTerraformName(inputs.TerraformName),
+		pathTemplate:   collectionPath,
+		itemAttributes: readableAttributes(inputs.Attributes),
+		itemsName:      snakeCase(collectionPath[strings.LastIndex(collectionPath, "/")+1:]),
+	}
+	ds.itemConverter = NewObjectConverter(inputs.TerraformName, ds.itemAttributes)
+
+	attributes := map[string]schema.Attribute{}
+	itemAttributes := map[string]schema.Attribute{}
+	for name, attribute := range entity.schema.Attributes {
+		itemAttributes[name] = computedAttribute(attribute)
+	}
+	var selectableNames, conditionNames []string
+	var exampleName string
+	for _, attr := range ds.itemAttributes {
+		selectableNames = append(selectableNames, attr.TerraformName)
+		if attr.Attributes == nil {
+			conditionNames = append(conditionNames, regexp.QuoteMeta(attr.TerraformName))
+		}
+		if attr.Identifying && strings.Contains(collectionPath, "{"+attr.SempName+"}") {
+			ds.parentAttributes = append(ds.parentAttributes, attr)
+			attributes[attr.TerraformName] = entity.schema.Attributes[attr.TerraformName]
+		} else if attr.Identifying && exampleName == "" {
+			exampleName = attr.TerraformName
+		}
+	}
+	sort.Strings(selectableNames)
+	attributes["where"] = schema.ListAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: fmt.Sprintf("Conditions the listed objects must all meet, in the form `<attribute><operator><value>`. The attribute is one of the attributes of `%s`, except nested attributes. The operators `==` and `!=` compare values, with `*` in the value matching any sequence of characters, for example `%s==orders.*`. The operators `<`, `>`, `<=` and `>=` compare numbers.", ds.itemsName, exampleName),
+		Optional:            true,
+		Validators: []validator.List{
+			listvalidator.ValueStringsAre(stringvalidator.RegexMatches(
+				regexp.MustCompile(`^(`+strings.Join(conditionNames, "|")+`)(`+strings.Join(quoteAll(whereOperators), "|")+`)`),
+				"must be a condition on an attribute of the listed objects",
+			)),
+		},
+	}
+	attributes["select"] = schema.ListAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: fmt.Sprintf("The attributes of `%s` to read from the broker, in addition to the identifying attributes. If set, the other attributes are null. All attributes are read if not set.", ds.itemsName),
+		Optional:            true,
+		Validators: []validator.List{
+			listvalidator.ValueStringsAre(stringvalidator.OneOf(selectableNames...)),
+		},
+	}
+	attributes[ds.itemsName] = schema.ListNestedAttribute{
+		MarkdownDescription: fmt.Sprintf("The objects found, with the attributes of the `solacebroker_%s` data source.", inputs.TerraformName),
+		Computed:            true,
+		NestedObject:        schema.NestedAttributeObject{Attributes: itemAttributes},
+	}
+	markdownDescription := fmt.Sprintf("Lists the `solacebroker_%s` objects of the collection `%s`, reading all pages of the collection from the broker. The objects can be filtered by conditions on their attributes, and the attributes read can be limited.", inputs.TerraformName, collectionPath)
+	if !strings.HasPrefix(inputs.TerraformName, "msg_vpn") {
+		markdownDescription = unsupportedResourceWarning + markdownDescription
+	}
+	ds.schema = schema.Schema{
+		Attributes:          attributes,
+		MarkdownDescription: markdownDescription,
+		DeprecationMessage:  inputs.DeprecationMessage,
+	}
+	return ds, true
+}
+
+func newBrokerListDataSourceClosure(templateDataSource brokerListDataSource) func() datasource.DataSource {
+	return func() datasource.DataSource {
+		var ds = templateDataSource
+		return &ds
+	}
+}
+
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = regexp.QuoteMeta(value)
+	}
+	return quoted
+}
+
+func (ds *brokerListDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, response *datasource.SchemaResponse) {
+	response.Schema = ds.schema
+}
+
+func (ds *brokerListDataSource) Metadata(_ context.Context, request datasource.MetadataRequest, response *datasource.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_" + ds.terraformName
+}
+
+func (ds *brokerListDataSource) Configure(_ context.Context, request datasource.ConfigureRequest, response *datasource.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+	client, ok := request.ProviderData.(*brokerClient)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected datasource configuration",
+			fmt.Sprintf("Unexpected type %T for provider data; expected %T.", request.ProviderData, client),
+		)
+		return
+	}
+	ds.client = client
+}
+
+func (ds *brokerListDataSource) Read(ctx context.Context, request datasource.ReadRequest, response *datasource.ReadResponse) {
+	client := ds.client
+	if err := client.checkBrokerRequirements(ctx); err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Broker check failed", err)
+		return
+	}
+	sempPath, err := resolveSempPath(ds.pathTemplate, ds.parentAttributes, request.Config.Raw)
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error generating SEMP path", err)
+		return
+	}
+	config := map[string]tftypes.Value{}
+	if err := request.Config.Raw.As(&config); err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error reading data source configuration", err)
+		return
+	}
+	query, err := ds.sempQuery(config["where"], config["select"])
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error generating SEMP query", err)
+		return
+	}
+	if query != "" {
+		sempPath += "?" + query
+	}
+	// Follows the nextPageUri of each page until all objects have been read
+	sempData, err := client.RequestWithoutBodyForGenerator(ctx, SempDetail.BasePath, http.MethodGet, sempPath, []map[string]any{})
+	if err != nil {
+		if errors.Is(err, semp.ErrResourceNotFound) {
+			addErrorToDiagnostics(&response.Diagnostics, fmt.Sprintf("Detected missing parent object of %v", sempPath), err)
+		} else {
+			addSempErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err, nil)
+		}
+		return
+	}
+	items := []tftypes.Value{}
+	for _, data := range sempData {
+		item, err := ds.itemConverter.ToTerraform(data)
+		if err != nil {
+			addErrorToDiagnostics(&response.Diagnostics, "SEMP response conversion failed", err)
+			return
+		}
+		items = append(items, item)
+	}
+	config[ds.itemsName] = tftypes.NewValue(tftypes.List{ElementType: ds.itemConverter.terraformType}, items)
+	response.State.Raw = tftypes.NewValue(request.Config.Raw.Type(), config)
+}
+
+// sempQuery returns the SEMP where and select query parameters of the configured conditions and attributes, with the
+// attribute names translated to their SEMP names
+func (ds *brokerListDataSource) sempQuery(where tftypes.Value, selected tftypes.Value) (string, error) {
+	sempNames := map[string]string{}
+	for _, attr := range ds.itemAttributes {
+		sempNames[attr.TerraformName] = attr.SempName
+	}
+	query := url.Values{}
+	var conditions []string
+	if err := listValue(where, &conditions); err != nil {
+		return "", err
+	}
+	var sempConditions []string
+	for _, condition := range conditions {
+		sempCondition, err := sempWhereCondition(condition, sempNames)
+		if err != nil {
+			return "", err
+		}
+		sempConditions = append(sempConditions, sempCondition)
+	}
+	if len(sempConditions) > 0 {
+		query.Set("where", strings.Join(sempConditions, ","))
+	}
+	var attributes []string
+	if err := listValue(selected, &attributes); err != nil {
+		return "", err
+	}
+	if len(attributes) > 0 {
+		var sempAttributes []string
+		for _, attr := range ds.itemAttributes {
+			if attr.Identifying {
+				sempAttributes = append(sempAttributes, attr.SempName)
+			}
+		}
+		for _, name := range attributes {
+			sempName, ok := sempNames[name]
+			if !ok {
+				return "", fmt.Errorf("unknown attribute %v", name)
+			}
+			sempAttributes = append(sempAttributes, sempName)
+		}
+		query.Set("select", strings.Join(sempAttributes, ","))
+	}
+	return query.Encode(), nil
+}
+
+// sempWhereCondition translates the attribute name of a condition to its SEMP name
+func sempWhereCondition(condition string, sempNames map[string]string) (string, error) {
+	for i := range condition {
+		for _, operator := range whereOperators {
+			if strings.HasPrefix(condition[i:], operator) {
+				sempName, ok := sempNames[condition[:i]]
+				if !ok {
+					return "", fmt.Errorf("unknown attribute %v in condition %v", condition[:i], condition)
+				}
+				return sempName + condition[i:], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no operator found in condition %v", condition)
+}
+
+// listValue reads a list of strings, leaving the target empty if the list is null
+func listValue(v tftypes.Value, target *[]string) error {
+	if v.IsNull() {
+		return nil
+	}
+	var elements []tftypes.Value
+	if err := v.As(&elements); err != nil {
+		return err
+	}
+	for _, element := range elements {
+		var s string
+		if err := element.As(&s); err != nil {
+			return err
+		}
+		*target = append(*target, s)
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func TestPluralTerraformName(t *testing.T) {
+	tests := map[string]string{
+		"msg_vpn_queue":                   "msg_vpn_queues",
+		"msg_vpn_proxy":                   "msg_vpn_proxies",
+		"client_cert_authority":           "client_cert_authorities",
+		"dmr_cluster_link_remote_address": "dmr_cluster_link_remote_addresses",
+		"msg_vpn_distributed_cache_cluster_global_caching_home_cluster_topic_prefix": "msg_vpn_distributed_cache_cluster_global_caching_home_cluster_topic_prefixes",
+	}
+	for name, want := range tests {
+		if got := pluralTerraformName(name); got != want {
+			t.Errorf("pluralTerraformName(%v) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestSempWhereCondition(t *testing.T) {
+	sempNames := map[string]string{"queue_name": "queueName", "max_bind_count": "maxBindCount"}
+	tests := []struct {
+		condition string
+		want      string
+		wantErr   bool
+	}{
+		{"queue_name==orders.*", "queueName==orders.*", false},
+		{"max_bind_count<=10", "maxBindCount<=10", false},
+		{"max_bind_count<10", "maxBindCount<10", false},
+		{"queue_name!=a==b", "queueName!=a==b", false},
+		{"owner==me", "", true},
+		{"queue_name", "", true},
+	}
+	for _, tt := range tests {
+		got, err := sempWhereCondition(tt.condition, sempNames)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("sempWhereCondition(%v) = %v, %v, want %v", tt.condition, got, err, tt.want)
+		}
+	}
+}
+
+func TestListDataSourceRead(t *testing.T) {
+	stringConverter := SimpleConverter[string]{TerraformType: tftypes.String}
+	ds, ok := newBrokerListDataSource(EntityInputs{
+		TerraformName: "msg_vpn_test",
+		PathTemplate:  "/msgVpns/{msgVpnName}/tests/{testName}",
+		Attributes: []*AttributeInfo{
+			{BaseType: String, SempName: "msgVpnName", TerraformName: "msg_vpn_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "testName", TerraformName: "test_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "description", TerraformName: "description", TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: Int64, SempName: "maxCount", TerraformName: "max_count", TerraformType: tftypes.Number, Converter: IntegerConverter{}},
+			{BaseType: String, SempName: "password", TerraformName: "password", Sensitive: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: Struct, SempName: "threshold", TerraformName: "threshold", Attributes: []*AttributeInfo{
+				{BaseType: Int64, SempName: "setValue", TerraformName: "set_value", TerraformType: tftypes.Number, Converter: IntegerConverter{}},
+				{BaseType: String, SempName: "secret", TerraformName: "secret", Sensitive: true, TerraformType: tftypes.String, Converter: stringConverter},
+			}},
+		},
+	})
+	if !ok || ds.terraformName != "msg_vpn_tests" || ds.itemsName != "tests" {
+		t.Fatalf("newBrokerListDataSource() = %v %v %v, want the msg_vpn_tests data source", ds.terraformName, ds.itemsName, ok)
+	}
+	if _, found := ds.schema.Attributes["tests"]; !found {
+		t.Fatalf("schema misses the tests attribute")
+	}
+
+	savedSempDetail := SempDetail
+	SempDetail = SempVersionDetail{BasePath: "/SEMP/v2/config"}
+	t.Cleanup(func() { SempDetail = savedSempDetail })
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if r.URL.Path != "/SEMP/v2/config/msgVpns/vpn/tests" || query.Get("where") != "testName==a*,maxCount>1" || query.Get("select") != "msgVpnName,testName,description" {
+			t.Errorf("unexpected request %v", r.URL)
+		}
+		response := map[string]any{"meta": map[string]any{"responseCode": 200}}
+		if query.Get("cursor") == "" {
+			response["data"] = []any{map[string]any{"msgVpnName": "vpn", "testName": "a1", "description": "first"}}
+			next := *r.URL
+			query.Set("cursor", "1")
+			next.RawQuery = query.Encode()
+			response["meta"].(map[string]any)["paging"] = map[string]any{"nextPageUri": server.URL + next.String()}
+		} else {
+			response["data"] = []any{map[string]any{"msgVpnName": "vpn", "testName": "a2", "threshold": map[string]any{"setValue": 80}}}
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+	ds.client = &brokerClient{Client: semp.NewClient(server.URL+SempDetail.BasePath, false, false, semp.BasicAuth("admin", "admin"), semp.Retries(0, 0, 0)), skipApiCheck: true}
+
+	ctx := context.Background()
+	schemaType := ds.schema.Type().TerraformType(ctx).(tftypes.Object)
+	strings := func(values ...string) tftypes.Value {
+		var elements []tftypes.Value
+		for _, value := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, value))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+	}
+	config := tftypes.NewValue(schemaType, map[string]tftypes.Value{
+		"msg_vpn_name": tftypes.NewValue(tftypes.String, "vpn"),
+		"where":        strings("test_name==a*", "max_count>1"),
+		"select":       strings("description"),
+		"tests":        tftypes.NewValue(schemaType.AttributeTypes["tests"], nil),
+	})
+	request := datasource.ReadRequest{Config: tfsdk.Config{Schema: ds.schema, Raw: config}}
+	response := &datasource.ReadResponse{State: tfsdk.State{Schema: ds.schema}}
+	ds.Read(ctx, request, response)
+	if response.Diagnostics.HasError() {
+		t.Fatalf("Read() diagnostics: %v", response.Diagnostics)
+	}
+
+	if !response.State.Raw.Type().Equal(schemaType) {
+		t.Fatalf("Read() state type %v, want the schema type %v", response.State.Raw.Type(), schemaType)
+	}
+	state := map[string]tftypes.Value{}
+	if err := response.State.Raw.As(&state); err != nil {
+		t.Fatal(err)
+	}
+	var items []tftypes.Value
+	if err := state["tests"].As(&items); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, item := range items {
+		attributes := map[string]tftypes.Value{}
+		if err := item.As(&attributes); err != nil {
+			t.Fatal(err)
+		}
+		var name string
+		if err := attributes["test_name"].As(&name); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+		if _, found := attributes["password"]; found {
+			t.Errorf("listed object has the write-only attribute password")
+		}
+	}
+	if len(names) != 2 || names[0] != "a1" || names[1] != "a2" {
+		t.Errorf("Read() listed %v, want a1 and a2 from both pages", names)
+	}
+}
@@ -0,0 +1,352 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	rschema "github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+// brokerListResource lists the objects of a resource type for terraform query, such as the queues of all message VPNs
+type brokerListResource struct {
+	schema           schema.Schema
+	pathTemplate     string           // the path of the collection
+	parentAttributes []*AttributeInfo // the identifying attributes of the parent object, in the collection path
+	ownAttributes    []*AttributeInfo // the identifying attributes of the listed objects within their collection
+	resource         brokerResource
+}
+
+var (
+	_ list.ListResourceWithConfigure = &brokerListResource{}
+)
+
+func newBrokerListResource(entity brokerEntity[rschema.Schema]) (brokerListResource, bool) {
+	collectionPath, ok := collectionPathTemplate(entity.pathTemplate)
+	if !ok || len(entity.identifyingAttributes) == 0 {
+		return brokerListResource{}, false
+	}
+	r := brokerListResource{
+		pathTemplate: collectionPath,
+		resource:     brokerResource(entity),
+	}
+	attributes := map[string]schema.Attribute{}
+	var ownNames []string
+	for _, attr := range entity.identifyingAttributes {
+		if strings.Contains(collectionPath, "{"+attr.SempName+"}") {
+			r.parentAttributes = append(r.parentAttributes, attr)
+			attributes[attr.TerraformName] = schema.StringAttribute{
+				MarkdownDescription: fmt.Sprintf("Lists only the objects with this `%s`. The objects of all parent objects are listed if not set.", attr.TerraformName),
+				Optional:            true,
+			}
+		} else {
+			r.ownAttributes = append(r.ownAttributes, attr)
+			ownNames = append(ownNames, "`"+attr.TerraformName+"`")
+		}
+	}
+	attributes["name_patterns"] = schema.ListAttribute{
+		ElementType:         types.StringType,
+		MarkdownDescription: fmt.Sprintf("Lists only the objects whose name matches one of the patterns. The name of an object is the value of %s, separated by commas if there are several. A pattern is a glob, in which `*` matches any sequence of characters and `?` any single character, or a regular expression prefixed by `re:`. Both must match the whole name.", strings.Join(ownNames, ", ")),
+		Optional:            true,
+	}
+	markdownDescription := fmt.Sprintf("Lists the `solacebroker_%s` objects on the broker for `terraform query`, reading all pages of the collection `%s`. The objects can be filtered by their parent objects and by name patterns. Objects created by the broker itself, with names starting with `#`, are not listed.", entity.terraformName, collectionPath)
+	if !strings.HasPrefix(entity.terraformName, "msg_vpn") {
+		markdownDescription = unsupportedResourceWarning + markdownDescription
+	}
+	r.schema = schema.Schema{
+		Attributes:          attributes,
+		MarkdownDescription: markdownDescription,
+		DeprecationMessage:  entity.schema.DeprecationMessage,
+	}
+	return r, true
+}
+
+func newBrokerListResourceClosure(templateListResource brokerListResource) func() list.ListResource {
+	return func() list.ListResource {
+		var r = templateListResource
+		return &r
+	}
+}
+
+func (r *brokerListResource) Metadata(ctx context.Context, request resource.MetadataRequest, response *resource.MetadataResponse) {
+	r.resource.Metadata(ctx, request, response)
+}
+
+func (r *brokerListResource) ListResourceConfigSchema(_ context.Context, _ list.ListResourceSchemaRequest, response *list.ListResourceSchemaResponse) {
+	response.Schema = r.schema
+}
+
+func (r *brokerListResource) Configure(ctx context.Context, request resource.ConfigureRequest, response *resource.ConfigureResponse) {
+	r.resource.Configure(ctx, request, response)
+}
+
+func (r *brokerListResource) List(ctx context.Context, request list.ListRequest, stream *list.ListResultsStream) {
+	var diags diag.Diagnostics
+	client := r.resource.client
+	if err := client.checkBrokerRequirements(ctx); err != nil {
+		addErrorToDiagnostics(&diags, "Broker check failed", err)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	config := map[string]tftypes.Value{}
+	if err := request.Config.Raw.As(&config); err != nil {
+		addErrorToDiagnostics(&diags, "Error reading list configuration", err)
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	parentValues := map[string]string{}
+	for _, attr := range r.parentAttributes {
+		if v := config[attr.TerraformName]; !v.IsNull() {
+			var value string
+			if err := v.As(&value); err != nil {
+				diags.AddAttributeError(path.Root(attr.TerraformName), "Error reading list configuration", err.Error())
+				continue
+			}
+			parentValues[attr.SempName] = value
+		}
+	}
+	var namePatterns []string
+	if err := listValue(config["name_patterns"], &namePatterns); err != nil {
+		diags.AddAttributeError(path.Root("name_patterns"), "Error reading list configuration", err.Error())
+	}
+	var patterns []*regexp.Regexp
+	for _, namePattern := range namePatterns {
+		pattern, err := CompilePattern(namePattern)
+		if err != nil {
+			diags.AddAttributeError(path.Root("name_patterns"), "Invalid name pattern", err.Error())
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+	query := ""
+	if !request.IncludeResource {
+		// Only the identifying attributes are needed without the resource
+		var sempNames []string
+		for _, attr := range r.resource.identifyingAttributes {
+			sempNames = append(sempNames, attr.SempName)
+		}
+		query = "?" + url.Values{"select": {strings.Join(sempNames, ",")}}.Encode()
+	}
+	stream.Results = func(push func(list.ListResult) bool) {
+		var count int64
+		segments := strings.Split(strings.TrimPrefix(r.pathTemplate, "/"), "/")
+		_, err := r.crawl(ctx, "", segments, parentValues, query, func(sempData map[string]any) bool {
+			result, ok := r.listResult(ctx, request, sempData, patterns)
+			if !ok {
+				return true
+			}
+			if !push(result) || result.Diagnostics.HasError() {
+				return false
+			}
+			count++
+			return request.Limit <= 0 || count < request.Limit
+		})
+		if err != nil {
+			var diags diag.Diagnostics
+			addSempErrorToDiagnostics(&diags, "SEMP call failed", err, nil)
+			push(list.ListResult{Diagnostics: diags})
+		}
+	}
+}
+
+// The parameters of a path segment, for example {bridgeName},{bridgeVirtualRouter}
+var pathParameterRex = regexp.MustCompile(`{([^{}]*)}`)
+
+// crawl visits the objects of the collection under each parent object, listing the parent collections to find the
+// parent objects whose identifiers are not given. It returns false if visit has stopped the crawling.
+func (r *brokerListResource) crawl(ctx context.Context, sempPath string, segments []string, parentValues map[string]string, query string, visit func(map[string]any) bool) (bool, error) {
+	for i, segment := range segments {
+		var sempNames []string
+		given := true
+		for _, match := range pathParameterRex.FindAllStringSubmatch(segment, -1) {
+			sempNames = append(sempNames, match[1])
+			_, ok := parentValues[match[1]]
+			given = given && ok
+		}
+		if given {
+			sempPath += "/" + substitutePathParameters(segment, parentValues)
+			continue
+		}
+		parents, err := r.readCollection(ctx, sempPath+"?"+url.Values{"select": {strings.Join(sempNames, ",")}}.Encode())
+		if err != nil {
+			return false, err
+		}
+		allSegments := strings.Split(strings.TrimPrefix(r.pathTemplate, "/"), "/")
+		parentType := resourceType("/" + strings.Join(allSegments[:len(allSegments)-len(segments)+i+1], "/"))
+		for _, parent := range parents {
+			values := map[string]string{}
+			for _, sempName := range sempNames {
+				values[sempName], _ = parent[sempName].(string)
+			}
+			if !matchesParentValues(parentType, values, parentValues) {
+				continue
+			}
+			more, err := r.crawl(ctx, sempPath+"/"+substitutePathParameters(segment, values), segments[i+1:], parentValues, query, visit)
+			if !more || err != nil {
+				return more, err
+			}
+		}
+		return true, nil
+	}
+	objects, err := r.readCollection(ctx, sempPath+query)
+	if err != nil {
+		return false, err
+	}
+	for _, object := range objects {
+		if !visit(object) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchesParentValues returns true if the identifiers of a parent object of the resource type match the given ones
+// and the parent object is not created by the broker itself
+func matchesParentValues(terraformName string, values map[string]string, parentValues map[string]string) bool {
+	for sempName, value := range values {
+		if IsSystemProvisionedObject(terraformName, value) {
+			return false
+		}
+		if parentValue, ok := parentValues[sempName]; ok && parentValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// resourceType returns the resource type of the objects at a path template, or "" if there is none
+func resourceType(pathTemplate string) string {
+	for _, inputs := range Entities {
+		if inputs.PathTemplate == pathTemplate {
+			return inputs.TerraformName
+		}
+	}
+	return ""
+}
+
+func substitutePathParameters(segment string, values map[string]string) string {
+	return pathParameterRex.ReplaceAllStringFunc(segment, func(parameter string) string {
+		return url.PathEscape(values[strings.Trim(parameter, "{}")])
+	})
+}
+
+// readCollection reads all pages of a collection, which is empty if its parent object doesn't exist
+func (r *brokerListResource) readCollection(ctx context.Context, sempPath string) ([]map[string]any, error) {
+	objects, err := r.resource.client.RequestWithoutBodyForGenerator(ctx, SempDetail.BasePath, http.MethodGet, sempPath, []map[string]any{})
+	if errors.Is(err, semp.ErrResourceNotFound) {
+		return nil, nil
+	}
+	return objects, err
+}
+
+// listResult returns the result for a listed object, or false if the object is filtered out by the name patterns or
+// created by the broker itself
+func (r *brokerListResource) listResult(ctx context.Context, request list.ListRequest, sempData map[string]any, patterns []*regexp.Regexp) (list.ListResult, bool) {
+	result := request.NewListResult(ctx)
+	var names []string
+	for _, attr := range r.ownAttributes {
+		value, _ := sempData[attr.SempName].(string)
+		if IsSystemProvisionedObject(r.resource.terraformName, value) {
+			return result, false
+		}
+		names = append(names, value)
+	}
+	if len(patterns) > 0 && !matchesAnyPattern(patterns, strings.Join(names, ",")) {
+		return result, false
+	}
+	identifierData := map[string]any{}
+	var displayName []string
+	for _, attr := range r.resource.identifyingAttributes {
+		identifierData[attr.SempName] = sempData[attr.SempName]
+		displayName = append(displayName, fmt.Sprintf("%v", sempData[attr.SempName]))
+	}
+	result.DisplayName = strings.Join(displayName, "/")
+	identifierState, err := r.resource.converter.ToTerraform(identifierData)
+	if err != nil {
+		addErrorToDiagnostics(&result.Diagnostics, "SEMP response conversion failed", err)
+		return result, true
+	}
+	result.Diagnostics.Append(r.resource.setIdentity(ctx, result.Identity, identifierState)...)
+	if !request.IncludeResource {
+		result.Resource = nil
+		return result, true
+	}
+	state, err := r.resourceState(ctx, request, sempData, identifierState)
+	if err != nil {
+		addErrorToDiagnostics(&result.Diagnostics, "SEMP response conversion failed", err)
+		return result, true
+	}
+	result.Resource.Raw = state
+	return result, true
+}
+
+// resourceState returns the state of a listed object as it is read after an import, with the attributes that have
+// their default value set to null
+func (r *brokerListResource) resourceState(ctx context.Context, request list.ListRequest, sempData map[string]any, identifierState tftypes.Value) (tftypes.Value, error) {
+	responseData, err := r.resource.converter.ToTerraform(sempData)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	noDefaults, err := r.resource.converter.ToTerraform(map[string]any{})
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	responseData, err = r.resource.resetResponse(r.resource.attributes, responseData, noDefaults, identifierState, false)
+	if err != nil {
+		return tftypes.Value{}, err
+	}
+	values := map[string]tftypes.Value{}
+	if err := responseData.As(&values); err != nil {
+		return tftypes.Value{}, err
+	}
+	// The converter also has the read-only attributes, which are not part of the resource
+	resourceType := request.ResourceSchema.Type().TerraformType(ctx).(tftypes.Object)
+	for name := range values {
+		if _, ok := resourceType.AttributeTypes[name]; !ok {
+			delete(values, name)
+		}
+	}
+	return tftypes.NewValue(resourceType, values), nil
+}
+
+func matchesAnyPattern(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
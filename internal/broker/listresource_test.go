@@ -0,0 +1,330 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{"orders*", "orders/eu", true},
+		{"orders?", "orders1", true},
+		{"orders?", "orders12", false},
+		{"a.b", "axb", false},
+		{"re:q[0-9]+", "q12", true},
+		{"re:q[0-9]+", "xq12", false},
+	}
+	for _, tt := range tests {
+		pattern, err := CompilePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("CompilePattern(%v) error = %v", tt.pattern, err)
+		}
+		if got := pattern.MatchString(tt.value); got != tt.want {
+			t.Errorf("CompilePattern(%v) matches %v = %v, want %v", tt.pattern, tt.value, got, tt.want)
+		}
+	}
+	for _, pattern := range []string{"", "re:["} {
+		if _, err := CompilePattern(pattern); err == nil {
+			t.Errorf("CompilePattern(%q) succeeded, want an error", pattern)
+		}
+	}
+}
+
+func TestListResourceList(t *testing.T) {
+	stringConverter := SimpleConverter[string]{TerraformType: tftypes.String}
+	entity := newBrokerResource(EntityInputs{
+		TerraformName: "msg_vpn_test",
+		PathTemplate:  "/msgVpns/{msgVpnName}/tests/{testName}",
+		Attributes: []*AttributeInfo{
+			{BaseType: String, SempName: "msgVpnName", TerraformName: "msg_vpn_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "testName", TerraformName: "test_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "description", TerraformName: "description", Default: "", TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "password", TerraformName: "password", Sensitive: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "status", TerraformName: "status", ReadOnly: true, TerraformType: tftypes.String, Converter: stringConverter},
+		},
+	})
+	r, ok := newBrokerListResource(entity)
+	if !ok || len(r.parentAttributes) != 1 || len(r.ownAttributes) != 1 {
+		t.Fatalf("newBrokerListResource() = %v, %v, want a list resource with one parent attribute", r, ok)
+	}
+	if _, ok := newBrokerListResource(newBrokerResource(EntityInputs{TerraformName: "broker", PathTemplate: "/"})); ok {
+		t.Errorf("newBrokerListResource() created a list resource for the broker object")
+	}
+
+	savedSempDetail := SempDetail
+	SempDetail = SempVersionDetail{BasePath: "/SEMP/v2/config"}
+	t.Cleanup(func() { SempDetail = savedSempDetail })
+	savedEntities := Entities
+	Entities = append(slices.Clone(Entities), EntityInputs{TerraformName: "msg_vpn", PathTemplate: "/msgVpns/{msgVpnName}"})
+	t.Cleanup(func() { Entities = savedEntities })
+	collections := map[string][]any{
+		"/SEMP/v2/config/msgVpns": {
+			map[string]any{"msgVpnName": "a"},
+			map[string]any{"msgVpnName": "#config-sync"},
+			map[string]any{"msgVpnName": "b"},
+		},
+		"/SEMP/v2/config/msgVpns/a/tests": {
+			map[string]any{"msgVpnName": "a", "testName": "orders", "description": "", "status": "up"},
+			map[string]any{"msgVpnName": "a", "testName": "payments", "description": "pay"},
+		},
+		"/SEMP/v2/config/msgVpns/b/tests": {
+			map[string]any{"msgVpnName": "b", "testName": "orders.eu", "description": "eu"},
+		},
+	}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.String())
+		data, ok := collections[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"responseCode": 400, "error": map[string]any{"status": "NOT_FOUND"}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data, "meta": map[string]any{"responseCode": 200}})
+	}))
+	t.Cleanup(server.Close)
+	r.resource.client = &brokerClient{Client: semp.NewClient(server.URL+SempDetail.BasePath, false, false, semp.BasicAuth("admin", "admin"), semp.Retries(0, 0, 0)), skipApiCheck: true}
+
+	ctx := context.Background()
+	identityResponse := &resource.IdentitySchemaResponse{}
+	(&brokerResourceWithIdentity{brokerResource: r.resource}).IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identityResponse)
+	configType := r.schema.Type().TerraformType(ctx)
+	strings := func(values ...string) tftypes.Value {
+		var elements []tftypes.Value
+		for _, value := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, value))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+	}
+	listObjects := func(msgVpnName any, namePatterns tftypes.Value, includeResource bool, limit int64) []list.ListResult {
+		requests = nil
+		request := list.ListRequest{
+			Config: tfsdk.Config{Schema: r.schema, Raw: tftypes.NewValue(configType, map[string]tftypes.Value{
+				"msg_vpn_name":  tftypes.NewValue(tftypes.String, msgVpnName),
+				"name_patterns": namePatterns,
+			})},
+			IncludeResource:        includeResource,
+			Limit:                  limit,
+			ResourceSchema:         entity.schema,
+			ResourceIdentitySchema: identityResponse.IdentitySchema,
+		}
+		stream := &list.ListResultsStream{}
+		r.List(ctx, request, stream)
+		var results []list.ListResult
+		for result := range stream.Results {
+			if result.Diagnostics.HasError() {
+				t.Fatalf("List() diagnostics: %v", result.Diagnostics)
+			}
+			results = append(results, result)
+		}
+		return results
+	}
+	displayNames := func(results []list.ListResult) []string {
+		var names []string
+		for _, result := range results {
+			names = append(names, result.DisplayName)
+		}
+		return names
+	}
+	noPatterns := tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil)
+
+	results := listObjects(nil, noPatterns, false, 0)
+	if got, want := displayNames(results), []string{"a/orders", "a/payments", "b/orders.eu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() of all message VPNs = %v, want %v", got, want)
+	}
+	if want := "/SEMP/v2/config/msgVpns/a/tests?select=msgVpnName%2CtestName"; len(requests) != 3 || requests[1] != want {
+		t.Errorf("List() requests = %v, want %v to read only the identifiers", requests, want)
+	}
+	identity := map[string]tftypes.Value{}
+	if err := results[0].Identity.Raw.As(&identity); err != nil {
+		t.Fatal(err)
+	}
+	if !identity["msg_vpn_name"].Equal(tftypes.NewValue(tftypes.String, "a")) || !identity["test_name"].Equal(tftypes.NewValue(tftypes.String, "orders")) {
+		t.Errorf("List() identity = %v, want a/orders", identity)
+	}
+	if results[0].Resource != nil {
+		t.Errorf("List() returned the resource although it was not requested")
+	}
+
+	if got, want := displayNames(listObjects("b", noPatterns, false, 0)), []string{"b/orders.eu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() of message VPN b = %v, want %v", got, want)
+	}
+	if len(requests) != 1 {
+		t.Errorf("List() of message VPN b requested %v, want only its collection", requests)
+	}
+	if got, want := displayNames(listObjects(nil, strings("orders*"), false, 0)), []string{"a/orders", "b/orders.eu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() with a glob = %v, want %v", got, want)
+	}
+	if got, want := displayNames(listObjects(nil, strings("re:pay.*", "x"), false, 0)), []string{"a/payments"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("List() with a regular expression = %v, want %v", got, want)
+	}
+	if got, want := displayNames(listObjects(nil, noPatterns, false, 2)), []string{"a/orders", "a/payments"}; !reflect.DeepEqual(got, want) || len(requests) != 2 {
+		t.Errorf("List() with limit = %v after %v, want %v without reading message VPN b", got, requests, want)
+	}
+	if got := displayNames(listObjects("c", noPatterns, false, 0)); len(got) != 0 {
+		t.Errorf("List() of a missing message VPN = %v, want none", got)
+	}
+
+	results = listObjects("a", strings("orders"), true, 0)
+	if len(results) != 1 || results[0].Resource == nil {
+		t.Fatalf("List() with resource = %v, want the resource of a/orders", results)
+	}
+	if !results[0].Resource.Raw.Type().Equal(entity.schema.Type().TerraformType(ctx)) {
+		t.Errorf("List() resource type %v, want the resource schema type", results[0].Resource.Raw.Type())
+	}
+	state := map[string]tftypes.Value{}
+	if err := results[0].Resource.Raw.As(&state); err != nil {
+		t.Fatal(err)
+	}
+	if !state["description"].IsNull() || !state["test_name"].Equal(tftypes.NewValue(tftypes.String, "orders")) {
+		t.Errorf("List() resource = %v, want the default description as null", state)
+	}
+
+	stream := &list.ListResultsStream{}
+	r.List(ctx, list.ListRequest{
+		Config: tfsdk.Config{Schema: r.schema, Raw: tftypes.NewValue(configType, map[string]tftypes.Value{
+			"msg_vpn_name":  tftypes.NewValue(tftypes.String, nil),
+			"name_patterns": strings("re:["),
+		})},
+		ResourceSchema:         entity.schema,
+		ResourceIdentitySchema: identityResponse.IdentitySchema,
+	}, stream)
+	for result := range stream.Results {
+		if !result.Diagnostics.HasError() {
+			t.Errorf("List() with an invalid pattern returned %v, want an error", result.DisplayName)
+		}
+	}
+}
+
+func TestSubstitutePathParameters(t *testing.T) {
+	values := map[string]string{"bridgeName": "a/b", "bridgeVirtualRouter": "primary"}
+	if got, want := substitutePathParameters("{bridgeName},{bridgeVirtualRouter}", values), "a%2Fb,primary"; got != want {
+		t.Errorf("substitutePathParameters() = %v, want %v", got, want)
+	}
+	if !matchesParentValues("msg_vpn_bridge", values, map[string]string{"bridgeName": "a/b"}) {
+		t.Errorf("matchesParentValues() = false for the given bridge name")
+	}
+	if matchesParentValues("msg_vpn_bridge", values, map[string]string{"bridgeVirtualRouter": "backup"}) {
+		t.Errorf("matchesParentValues() = true for another virtual router")
+	}
+	if matchesParentValues("msg_vpn", map[string]string{"msgVpnName": "#config-sync"}, nil) {
+		t.Errorf("matchesParentValues() = true for a system provisioned message VPN")
+	}
+	if !matchesParentValues("msg_vpn_queue", map[string]string{"queueName": "#P2P/QUE/v:router/client"}, nil) {
+		t.Errorf("matchesParentValues() = false for a queue named with #")
+	}
+}
+
+func TestListResourceSystemProvisioned(t *testing.T) {
+	stringConverter := SimpleConverter[string]{TerraformType: tftypes.String}
+	entity := newBrokerResource(EntityInputs{
+		TerraformName: "msg_vpn_acl_profile_publish_topic_exception",
+		PathTemplate:  "/msgVpns/{msgVpnName}/aclProfiles/{aclProfileName}/publishTopicExceptions/{publishTopicExceptionSyntax},{publishTopicException}",
+		Attributes: []*AttributeInfo{
+			{BaseType: String, SempName: "msgVpnName", TerraformName: "msg_vpn_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "aclProfileName", TerraformName: "acl_profile_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "publishTopicExceptionSyntax", TerraformName: "publish_topic_exception_syntax", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "publishTopicException", TerraformName: "publish_topic_exception", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+		},
+	})
+	r, ok := newBrokerListResource(entity)
+	if !ok {
+		t.Fatalf("newBrokerListResource() did not create a list resource")
+	}
+
+	savedSempDetail := SempDetail
+	SempDetail = SempVersionDetail{BasePath: "/SEMP/v2/config"}
+	t.Cleanup(func() { SempDetail = savedSempDetail })
+	savedEntities := Entities
+	Entities = append(slices.Clone(Entities),
+		EntityInputs{TerraformName: "msg_vpn", PathTemplate: "/msgVpns/{msgVpnName}"},
+		EntityInputs{TerraformName: "msg_vpn_acl_profile", PathTemplate: "/msgVpns/{msgVpnName}/aclProfiles/{aclProfileName}"})
+	t.Cleanup(func() { Entities = savedEntities })
+	collections := map[string][]any{
+		"/SEMP/v2/config/msgVpns": {
+			map[string]any{"msgVpnName": "#config-sync"},
+			map[string]any{"msgVpnName": "a"},
+		},
+		"/SEMP/v2/config/msgVpns/a/aclProfiles": {
+			map[string]any{"msgVpnName": "a", "aclProfileName": "#acl-profile"},
+			map[string]any{"msgVpnName": "a", "aclProfileName": "default"},
+		},
+		"/SEMP/v2/config/msgVpns/a/aclProfiles/default/publishTopicExceptions": {
+			map[string]any{"msgVpnName": "a", "aclProfileName": "default", "publishTopicExceptionSyntax": "smf", "publishTopicException": "#P2P/v:router/>"},
+			map[string]any{"msgVpnName": "a", "aclProfileName": "default", "publishTopicExceptionSyntax": "smf", "publishTopicException": "orders/>"},
+		},
+	}
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		data, ok := collections[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]any{"meta": map[string]any{"responseCode": 400, "error": map[string]any{"status": "NOT_FOUND"}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data, "meta": map[string]any{"responseCode": 200}})
+	}))
+	t.Cleanup(server.Close)
+	r.resource.client = &brokerClient{Client: semp.NewClient(server.URL+SempDetail.BasePath, false, false, semp.BasicAuth("admin", "admin"), semp.Retries(0, 0, 0)), skipApiCheck: true}
+
+	ctx := context.Background()
+	identityResponse := &resource.IdentitySchemaResponse{}
+	(&brokerResourceWithIdentity{brokerResource: r.resource}).IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identityResponse)
+	stream := &list.ListResultsStream{}
+	r.List(ctx, list.ListRequest{
+		Config: tfsdk.Config{Schema: r.schema, Raw: tftypes.NewValue(r.schema.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"msg_vpn_name":     tftypes.NewValue(tftypes.String, nil),
+			"acl_profile_name": tftypes.NewValue(tftypes.String, nil),
+			"name_patterns":    tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		})},
+		ResourceSchema:         entity.schema,
+		ResourceIdentitySchema: identityResponse.IdentitySchema,
+	}, stream)
+	var displayNames []string
+	for result := range stream.Results {
+		if result.Diagnostics.HasError() {
+			t.Fatalf("List() diagnostics: %v", result.Diagnostics)
+		}
+		displayNames = append(displayNames, result.DisplayName)
+	}
+	// ACL exceptions for #P2P topics are configured, unlike the message VPNs and ACL profiles named with #
+	if want := []string{"a/default/smf/#P2P/v:router/>", "a/default/smf/orders/>"}; !reflect.DeepEqual(displayNames, want) {
+		t.Errorf("List() = %v, want %v", displayNames, want)
+	}
+	if len(requests) != 3 {
+		t.Errorf("List() requests = %v, want none for the system provisioned objects", requests)
+	}
+}
@@ -0,0 +1,67 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prefix of patterns that are regular expressions rather than globs
+const RegexPatternPrefix = "re:"
+
+// CompilePattern compiles a glob, in which "*" matches any sequence of characters including "/" and "?" any single
+// character, or a regular expression prefixed by "re:". Both must match the whole value.
+func CompilePattern(pattern string) (*regexp.Regexp, error) {
+	if strings.HasPrefix(pattern, RegexPatternPrefix) {
+		rex, err := regexp.Compile("^(?:" + strings.TrimPrefix(pattern, RegexPatternPrefix) + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		return rex, nil
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	rex := regexp.QuoteMeta(pattern)
+	rex = strings.ReplaceAll(rex, `\*`, ".*")
+	rex = strings.ReplaceAll(rex, `\?`, ".")
+	return regexp.MustCompile("^" + rex + "$"), nil
+}
+
+// IsSystemProvisioned returns true if an identifying attribute value names an object created by the broker itself,
+// which cannot be managed by Terraform
+func IsSystemProvisioned(value string) bool {
+	return strings.HasPrefix(value, "#") && value != "#DEAD_MSG_QUEUE"
+}
+
+// The resource types whose objects with names starting with "#" are created by the broker itself. Objects of other
+// types may be configured with such names, for example ACL exceptions for "#P2P/..." topics.
+// Workaround while waiting for SOL-117252
+var systemProvisionedTypes = map[string]bool{
+	"msg_vpn":                 true,
+	"msg_vpn_acl_profile":     true,
+	"msg_vpn_client_profile":  true,
+	"msg_vpn_client_username": true,
+}
+
+// IsSystemProvisionedObject returns true if an identifying attribute value of an object of the resource type names an
+// object created by the broker itself
+func IsSystemProvisionedObject(terraformName string, value string) bool {
+	return systemProvisionedTypes[terraformName] && IsSystemProvisioned(value)
+}
@@ -22,6 +22,9 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -30,7 +33,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var _ provider.Provider = &BrokerProvider{}
+var (
+	_ provider.Provider                       = &BrokerProvider{}
+	_ provider.ProviderWithListResources      = &BrokerProvider{}
+	_ provider.ProviderWithEphemeralResources = &BrokerProvider{}
+	_ provider.ProviderWithFunctions          = &BrokerProvider{}
+)
 var ProviderVersion string
 
 type BrokerProvider struct {
@@ -63,6 +71,40 @@ func (p *BrokerProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				Optional:            true,
 				Sensitive:           true,
 			},
+			"oauth_token_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of an OAuth2 token endpoint. If set, bearer tokens are obtained from this endpoint with the client credentials grant and refreshed automatically. Requires oauth_client_id and oauth_client_secret. Conflicts with bearer_token, username and password.",
+				Optional:            true,
+			},
+			"oauth_client_id": schema.StringAttribute{
+				MarkdownDescription: "The OAuth2 client ID to obtain bearer tokens with. Requires oauth_token_url.",
+				Optional:            true,
+			},
+			"oauth_client_secret": schema.StringAttribute{
+				MarkdownDescription: "The OAuth2 client secret to obtain bearer tokens with. Requires oauth_token_url.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"oauth_scopes": schema.StringAttribute{
+				MarkdownDescription: "A space-separated list of scopes to request with the OAuth2 bearer tokens. Requires oauth_token_url.",
+				Optional:            true,
+			},
+			"client_certificate": schema.StringAttribute{
+				MarkdownDescription: "The PEM encoded client certificate (chain) to present to the broker for client certificate authentication. Requires client_private_key or client_private_key_file and conflicts with client_certificate_file.",
+				Optional:            true,
+			},
+			"client_certificate_file": schema.StringAttribute{
+				MarkdownDescription: "The path of a file containing the PEM encoded client certificate (chain) to present to the broker for client certificate authentication. Requires client_private_key or client_private_key_file and conflicts with client_certificate.",
+				Optional:            true,
+			},
+			"client_private_key": schema.StringAttribute{
+				MarkdownDescription: "The PEM encoded private key of the client certificate. Requires client_certificate or client_certificate_file and conflicts with client_private_key_file.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"client_private_key_file": schema.StringAttribute{
+				MarkdownDescription: "The path of a file containing the PEM encoded private key of the client certificate. Requires client_certificate or client_certificate_file and conflicts with client_private_key.",
+				Optional:            true,
+			},
 			"retries": schema.Int64Attribute{
 				MarkdownDescription: "The number of retries for a SEMP call. The default value is 10.",
 				Optional:            true,
@@ -87,10 +129,26 @@ func (p *BrokerProvider) Schema(_ context.Context, _ provider.SchemaRequest, res
 				MarkdownDescription: "Disable validation of server SSL certificates, accept/ignore self-signed. The default value is false.",
 				Optional:            true,
 			},
+			"ca_certificate": schema.StringAttribute{
+				MarkdownDescription: "PEM encoded CA certificates to trust when validating the broker's SEMP server certificate, instead of the host's root CA set. Conflicts with ca_bundle_file.",
+				Optional:            true,
+			},
+			"ca_bundle_file": schema.StringAttribute{
+				MarkdownDescription: "The path of a file containing PEM encoded CA certificates to trust when validating the broker's SEMP server certificate, instead of the host's root CA set. Conflicts with ca_certificate.",
+				Optional:            true,
+			},
+			"tls_server_name": schema.StringAttribute{
+				MarkdownDescription: "The server name to verify the broker's SEMP server certificate against, if it differs from the host in url. Useful when the broker is reached through an IP address or a load balancer.",
+				Optional:            true,
+			},
 			"skip_api_check": schema.BoolAttribute{
 				MarkdownDescription: "Disable validation of the broker SEMP API for supported platform and minimum version. The default value is false.",
 				Optional:            true,
 			},
+			"update_mode": schema.StringAttribute{
+				MarkdownDescription: "How objects are updated on the broker. With `put` the complete configuration of the object is sent, resetting attributes that are not configured to their defaults. With `patch` only the attributes that have changed since the last apply are sent; PUT is still used if a removed attribute cannot be reset to its broker default otherwise. The default value is `put`.",
+				Optional:            true,
+			},
 		},
 		MarkdownDescription: "",
 	}
@@ -122,7 +180,19 @@ func (p *BrokerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	ctx = tflog.SetField(ctx, "solacebroker_url", strings.Trim(config.Url.String(), "\""))
 	ctx = tflog.SetField(ctx, "solacebroker_provider_version", p.Version)
 	tflog.Debug(ctx, "Creating SEMP client")
+	// The ephemeral resources only reach token endpoints, so they work without broker credentials, for example to
+	// obtain the bearer token of another provider instance
+	tokenClient, d := tokenClient(&config)
+	if d != nil {
+		resp.Diagnostics.Append(d)
+		return
+	}
 	client, d := client(&config)
+	if d != nil && d.Summary() == noCredentialsSummary {
+		tflog.Info(ctx, "Solacebroker provider has no credentials, only ephemeral resources can be used")
+		client = &brokerClient{Client: tokenClient.Client, credentialsError: fmt.Errorf("%s: %s", d.Summary(), d.Detail())}
+		d = nil
+	}
 	if d != nil {
 		resp.Diagnostics.Append(d)
 		if resp.Diagnostics.HasError() {
@@ -132,7 +202,8 @@ func (p *BrokerProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	tflog.Info(ctx, "Solacebroker provider client config success")
 	resp.ResourceData = client
 	resp.DataSourceData = client
-	forceBrokerRequirementsCheck()
+	resp.ListResourceData = client
+	resp.EphemeralResourceData = tokenClient
 }
 
 func (p *BrokerProvider) Resources(_ context.Context) []func() resource.Resource {
@@ -143,18 +214,42 @@ func (p *BrokerProvider) DataSources(_ context.Context) []func() datasource.Data
 	return DataSources
 }
 
+func (p *BrokerProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return EphemeralResources
+}
+
+func (p *BrokerProvider) Functions(_ context.Context) []func() function.Function {
+	return Functions
+}
+
+func (p *BrokerProvider) ListResources(_ context.Context) []func() list.ListResource {
+	return ListResources
+}
+
 type providerData struct {
 	Url                    types.String `tfsdk:"url"`
 	Username               types.String `tfsdk:"username"`
 	Password               types.String `tfsdk:"password"`
 	BearerToken            types.String `tfsdk:"bearer_token"`
+	OauthTokenUrl          types.String `tfsdk:"oauth_token_url"`
+	OauthClientId          types.String `tfsdk:"oauth_client_id"`
+	OauthClientSecret      types.String `tfsdk:"oauth_client_secret"`
+	OauthScopes            types.String `tfsdk:"oauth_scopes"`
+	ClientCertificate      types.String `tfsdk:"client_certificate"`
+	ClientCertificateFile  types.String `tfsdk:"client_certificate_file"`
+	ClientPrivateKey       types.String `tfsdk:"client_private_key"`
+	ClientPrivateKeyFile   types.String `tfsdk:"client_private_key_file"`
 	Retries                types.Int64  `tfsdk:"retries"`
 	RetryMinInterval       types.String `tfsdk:"retry_min_interval"`
 	RetryMaxInterval       types.String `tfsdk:"retry_max_interval"`
 	RequestTimeoutDuration types.String `tfsdk:"request_timeout_duration"`
 	RequestMinInterval     types.String `tfsdk:"request_min_interval"`
 	InsecureSkipVerify     types.Bool   `tfsdk:"insecure_skip_verify"`
+	CaCertificate          types.String `tfsdk:"ca_certificate"`
+	CaBundleFile           types.String `tfsdk:"ca_bundle_file"`
+	TlsServerName          types.String `tfsdk:"tls_server_name"`
 	SkipApiCheck           types.Bool   `tfsdk:"skip_api_check"`
+	UpdateMode             types.String `tfsdk:"update_mode"`
 }
 
 func New(version string) func() provider.Provider {
@@ -0,0 +1,85 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+var (
+	aclProfileReference            = AttributeReference{TerraformName: "msg_vpn_acl_profile", Attribute: "acl_profile_name"}
+	clientProfileReference         = AttributeReference{TerraformName: "msg_vpn_client_profile", Attribute: "client_profile_name"}
+	clientUsernameReference        = AttributeReference{TerraformName: "msg_vpn_client_username", Attribute: "client_username"}
+	queueReference                 = AttributeReference{TerraformName: "msg_vpn_queue", Attribute: "queue_name"}
+	queueTemplateReference         = AttributeReference{TerraformName: "msg_vpn_queue_template", Attribute: "queue_template_name"}
+	topicEndpointTemplateReference = AttributeReference{TerraformName: "msg_vpn_topic_endpoint_template", Attribute: "topic_endpoint_template_name"}
+)
+
+// The attributes that name other objects in the same scope, for example in the same Message VPN, indexed by resource
+// type and attribute. SEMP does not describe these relationships, so they complement the generated entities.
+var attributeReferences = map[string]map[string]AttributeReference{
+	"msg_vpn_authorization_group": {
+		"acl_profile_name":    aclProfileReference,
+		"client_profile_name": clientProfileReference,
+	},
+	"msg_vpn_bridge_remote_msg_vpn": {
+		"queue_binding":                 queueReference,
+		"unidirectional_client_profile": clientProfileReference,
+	},
+	"msg_vpn_client_profile": {
+		"api_queue_management_copy_from_on_create_template_name":          queueTemplateReference,
+		"api_topic_endpoint_management_copy_from_on_create_template_name": topicEndpointTemplateReference,
+	},
+	"msg_vpn_client_username": {
+		"acl_profile_name":    aclProfileReference,
+		"client_profile_name": clientProfileReference,
+	},
+	"msg_vpn_jndi_queue": {
+		"physical_name": queueReference,
+	},
+	"msg_vpn_kafka_sender_queue_binding": {
+		"queue_name": queueReference,
+	},
+	"msg_vpn_mqtt_session": {
+		"owner":                clientUsernameReference,
+		"queue_dead_msg_queue": queueReference,
+	},
+	"msg_vpn_queue": {
+		"dead_msg_queue": queueReference,
+		"owner":          clientUsernameReference,
+	},
+	"msg_vpn_queue_template": {
+		"dead_msg_queue": queueReference,
+	},
+	"msg_vpn_rest_delivery_point": {
+		"client_profile_name": clientProfileReference,
+	},
+	"msg_vpn_rest_delivery_point_queue_binding": {
+		"queue_binding_name": queueReference,
+	},
+	"msg_vpn_topic_endpoint": {
+		"dead_msg_queue": queueReference,
+		"owner":          clientUsernameReference,
+	},
+	"msg_vpn_topic_endpoint_template": {
+		"dead_msg_queue": queueReference,
+	},
+}
+
+func addAttributeReferences(inputs EntityInputs) {
+	for _, attr := range inputs.Attributes {
+		if reference, ok := attributeReferences[inputs.TerraformName][attr.TerraformName]; ok {
+			attr.References = &reference
+		}
+	}
+}
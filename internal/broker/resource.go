@@ -17,20 +17,27 @@
 package broker
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 
@@ -39,7 +46,15 @@ import (
 
 type brokerResource brokerEntity[schema.Schema]
 
+// brokerResourceWithIdentity is a resource with identifying attributes, which make up its resource identity. The
+// broker object itself has none and is the only resource without identity.
+type brokerResourceWithIdentity struct {
+	brokerResource
+}
+
 const (
+	updateModePut                   = "put"
+	updateModePatch                 = "patch"
 	defaults                        = "defaults"
 	defaultObjectName               = "default"
 	minRequiredBrokerSempApiVersion = "2.33" // Shipped with broker version 10.3
@@ -58,60 +73,65 @@ var (
 	_ resource.ResourceWithConfigValidators = &brokerResource{}
 	_ resource.ResourceWithImportState      = &brokerResource{}
 	_ resource.ResourceWithUpgradeState     = &brokerResource{}
+	_ resource.ResourceWithIdentity         = &brokerResourceWithIdentity{}
 )
 
-var (
-	skipApiCheck      = false
-	apiAlreadyChecked = false
+// brokerClient is the SEMP client of a provider configuration together with the state of its broker API check.
+// Each configured provider instance has its own, so aliased providers for different brokers don't interfere.
+type brokerClient struct {
+	*semp.Client
+	credentialsError  error // set if the provider has no credentials, fails any access to broker objects
+	skipApiCheck      bool
+	updateMode        string
+	apiAlreadyChecked bool
 	lock              sync.Mutex
-)
+}
 
 func newBrokerResource(inputs EntityInputs) brokerEntity[schema.Schema] {
 	return newBrokerEntity(inputs, true)
 }
 
-func newBrokerResourceGenerator(inputs EntityInputs) func() resource.Resource {
-	return newBrokerResourceClosure(newBrokerResource(inputs))
-}
-
 func newBrokerResourceClosure(templateEntity brokerEntity[schema.Schema]) func() resource.Resource {
 	return func() resource.Resource {
 		var r = brokerResource(templateEntity)
+		if len(r.identifyingAttributes) != 0 {
+			return &brokerResourceWithIdentity{brokerResource: r}
+		}
 		return &r
 	}
 }
 
-func forceBrokerRequirementsCheck() {
-	apiAlreadyChecked = false
-}
-
-func checkBrokerRequirements(ctx context.Context, client *semp.Client) error {
-	if !skipApiCheck && !apiAlreadyChecked {
-		lock.Lock()
-		defer lock.Unlock()
-		if apiAlreadyChecked {
-			return nil
-		}
-		path := "/about/api"
-		result, err := client.RequestWithoutBody(ctx, http.MethodGet, path)
-		if err != nil {
-			return err
-		}
-		// To support broker developer versions ignore "+" in the returned version
-		brokerSempVersion, err := version.NewVersion(strings.Replace(result["sempVersion"].(string), "+", "", -1))
-		if err != nil {
-			return err
-		}
-		minSempVersion, _ := version.NewVersion(minRequiredBrokerSempApiVersion)
-		if brokerSempVersion.LessThan(minSempVersion) {
-			return fmt.Errorf("broker SEMP API version %s does not meet provider required minimum SEMP API version: %s", brokerSempVersion, minSempVersion)
-		}
-		brokerPlatform := result["platform"].(string)
-		if brokerPlatform != SempDetail.Platform {
-			return fmt.Errorf("broker platform \"%s\" does not match provider supported platform: %s", BrokerPlatformName[brokerPlatform], BrokerPlatformName[SempDetail.Platform])
-		}
-		apiAlreadyChecked = true
+func (c *brokerClient) checkBrokerRequirements(ctx context.Context) error {
+	if c.credentialsError != nil {
+		return c.credentialsError
+	}
+	if c.skipApiCheck {
+		return nil
 	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.apiAlreadyChecked {
+		return nil
+	}
+	path := "/about/api"
+	result, err := c.RequestWithoutBody(ctx, http.MethodGet, path)
+	if err != nil {
+		return err
+	}
+	// To support broker developer versions ignore "+" in the returned version
+	brokerSempVersion, err := version.NewVersion(strings.Replace(result["sempVersion"].(string), "+", "", -1))
+	if err != nil {
+		return err
+	}
+	minSempVersion, _ := version.NewVersion(minRequiredBrokerSempApiVersion)
+	if brokerSempVersion.LessThan(minSempVersion) {
+		return fmt.Errorf("broker SEMP API version %s does not meet provider required minimum SEMP API version: %s", brokerSempVersion, minSempVersion)
+	}
+	brokerPlatform := result["platform"].(string)
+	if brokerPlatform != SempDetail.Platform {
+		return fmt.Errorf("broker platform \"%s\" does not match provider supported platform: %s", BrokerPlatformName[brokerPlatform], BrokerPlatformName[SempDetail.Platform])
+	}
+	c.apiAlreadyChecked = true
 	return nil
 }
 
@@ -209,7 +229,8 @@ func (r *brokerResource) resetResponse(attributes []*AttributeInfo, response tft
 					responseValues[name] = state
 				} // else leave attr response unchanged
 			}
-		} else if stateExists && attr.Sensitive {
+		} else if stateExists && (attr.Sensitive || attr.WriteOnlyVersion) {
+			// the broker doesn't return secrets, and versions of write-only attributes are only known to the state
 			responseValues[name] = state
 		} else {
 			responseValues[name] = tftypes.NewValue(attr.TerraformType, nil)
@@ -245,6 +266,240 @@ func (r *brokerResource) findBrokerDefaults(attributes []*AttributeInfo, respons
 	return r.converter.FromTerraform(tftypes.NewValue(request.Type(), defaultValues))
 }
 
+// writeOnlyData returns the SEMP data of the write-only attributes set in the configuration
+func (r *brokerResource) writeOnlyData(config tftypes.Value) (map[string]any, error) {
+	configValues := map[string]tftypes.Value{}
+	if err := config.As(&configValues); err != nil {
+		return nil, err
+	}
+	sempData := map[string]any{}
+	for _, attr := range r.attributes {
+		v := configValues[attr.TerraformName]
+		if !attr.WriteOnly || !v.IsKnown() || v.IsNull() {
+			continue
+		}
+		value, err := attr.Converter.FromTerraform(v)
+		if err != nil {
+			return nil, err
+		}
+		sempData[attr.SempName] = value
+	}
+	return sempData, nil
+}
+
+// writeOnlyVersionChanged returns true if the version of a write-only attribute differs between prior state and plan
+func writeOnlyVersionChanged(attr *AttributeInfo, planValues map[string]tftypes.Value, stateValues map[string]tftypes.Value) bool {
+	name := strings.TrimSuffix(attr.TerraformName, writeOnlySuffix) + writeOnlyVersionSuffix
+	planVersion, inPlan := planValues[name]
+	stateVersion, inState := stateValues[name]
+	return inPlan != inState || inPlan && !planVersion.Equal(stateVersion)
+}
+
+// decodeBrokerDefaults returns the broker defaults recorded in the private data, keyed by SEMP name
+func decodeBrokerDefaults(defaultsJson []byte) (map[string]any, error) {
+	brokerDefaults := map[string]any{}
+	if defaultsJson != nil {
+		decoder := json.NewDecoder(bytes.NewReader(defaultsJson))
+		// keep numbers as they are so that large integers are sent back unchanged
+		decoder.UseNumber()
+		if err := decoder.Decode(&brokerDefaults); err != nil {
+			return nil, err
+		}
+	}
+	return brokerDefaults, nil
+}
+
+// changedSempData returns the SEMP data to PATCH an object from its prior state to the plan: the identifying
+// attributes, the attributes that have changed and the attributes these require. Attributes removed from the
+// configuration are reset to the broker defaults recorded in the private data. If an attribute cannot be reset this
+// way, the returned reason explains why the whole object must be PUT instead. Write-only values are sent when their
+// version changes, when they are required by a changed attribute or when they replace an attribute from the state.
+func (r *brokerResource) changedSempData(plan tftypes.Value, state tftypes.Value, defaultsJson []byte, writeOnlyData map[string]any) (map[string]any, string, error) {
+	planData, err := r.converter.FromTerraform(plan)
+	if err != nil {
+		return nil, "", err
+	}
+	stateData, err := r.converter.FromTerraform(state)
+	if err != nil {
+		return nil, "", err
+	}
+	brokerDefaults, err := decodeBrokerDefaults(defaultsJson)
+	if err != nil {
+		return nil, "", err
+	}
+	planValues, _ := planData.(map[string]any)
+	stateValues, _ := stateData.(map[string]any)
+	planAttributes := map[string]tftypes.Value{}
+	if err := plan.As(&planAttributes); err != nil {
+		return nil, "", err
+	}
+	stateAttributes := map[string]tftypes.Value{}
+	if err := state.As(&stateAttributes); err != nil {
+		return nil, "", err
+	}
+	attributesByTerraformName := map[string]*AttributeInfo{}
+	for _, attr := range r.attributes {
+		attributesByTerraformName[attr.TerraformName] = attr
+	}
+	patchData := map[string]any{}
+	for _, attr := range r.attributes {
+		if attr.ReadOnly && !attr.Identifying {
+			continue
+		}
+		planValue, inPlan := planValues[attr.SempName]
+		stateValue, inState := stateValues[attr.SempName]
+		switch {
+		case attr.Identifying:
+			patchData[attr.SempName] = planValue
+		case attr.WriteOnly:
+			if value, ok := writeOnlyData[attr.SempName]; ok && (inState || writeOnlyVersionChanged(attr, planAttributes, stateAttributes)) {
+				patchData[attr.SempName] = value
+			}
+		case inPlan && (!inState || !reflect.DeepEqual(planValue, stateValue)):
+			patchData[attr.SempName] = planValue
+			// attributes that must be sent together
+			for _, required := range attr.Requires {
+				if requiredAttr, ok := attributesByTerraformName[required]; ok {
+					if requiredValue, ok := planValues[requiredAttr.SempName]; ok {
+						patchData[requiredAttr.SempName] = requiredValue
+					} else if requiredValue, ok := writeOnlyData[requiredAttr.SempName]; ok {
+						patchData[requiredAttr.SempName] = requiredValue
+					}
+				}
+			}
+		case !inPlan && inState:
+			if _, ok := writeOnlyData[attr.SempName]; ok {
+				// replaced by its write-only attribute
+				continue
+			}
+			brokerDefault, ok := brokerDefaults[attr.SempName]
+			if !ok || brokerDefault == nil {
+				return nil, fmt.Sprintf("attribute %v has been removed and its broker default is unknown", attr.TerraformName), nil
+			}
+			patchData[attr.SempName] = brokerDefault
+		}
+	}
+	return patchData, "", nil
+}
+
+// requiresDisabledRex matches the sentence of the SEMP attribute descriptions that names the enabled attributes the
+// broker sets to false while the attribute is changed
+var requiresDisabledRex = regexp.MustCompile(`as ([a-z0-9_]+(?:(?:, | and )[a-z0-9_]+)*) will be temporarily set to false`)
+
+// addRequiresDisabled sets the RequiresDisabled attributes from the attribute descriptions. SEMP describes this
+// relationship only in text, so it is derived here rather than in the generated entities.
+func addRequiresDisabled(inputs EntityInputs) {
+	for _, attr := range inputs.Attributes {
+		match := requiresDisabledRex.FindStringSubmatch(attr.MarkdownDescription)
+		if match == nil {
+			continue
+		}
+		attr.RequiresDisabled = regexp.MustCompile(`, | and `).Split(match[1], -1)
+	}
+}
+
+// enableAttributesToToggle returns the enabled attributes that must be set to false to change the attributes that
+// differ between prior state and plan, the ones of these to enable again afterwards according to the plan, and the
+// names of the changed attributes. Only attributes that are currently enabled are disabled. An enabled attribute that
+// is null has its default value, or the broker default recorded in the private data.
+func (r *brokerResource) enableAttributesToToggle(plan tftypes.Value, state tftypes.Value, defaultsJson []byte) ([]*AttributeInfo, []*AttributeInfo, []string, error) {
+	brokerDefaults, err := decodeBrokerDefaults(defaultsJson)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	enabled := func(values map[string]any, attr *AttributeInfo) bool {
+		if value, ok := values[attr.SempName]; ok {
+			return value == true
+		}
+		if attr.Default != nil {
+			return attr.Default == true
+		}
+		return brokerDefaults[attr.SempName] == true
+	}
+	planData, err := r.converter.FromTerraform(plan)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	stateData, err := r.converter.FromTerraform(state)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	planValues, _ := planData.(map[string]any)
+	stateValues, _ := stateData.(map[string]any)
+	planAttributes := map[string]tftypes.Value{}
+	if err := plan.As(&planAttributes); err != nil {
+		return nil, nil, nil, err
+	}
+	stateAttributes := map[string]tftypes.Value{}
+	if err := state.As(&stateAttributes); err != nil {
+		return nil, nil, nil, err
+	}
+	attributesByTerraformName := map[string]*AttributeInfo{}
+	for _, attr := range r.attributes {
+		attributesByTerraformName[attr.TerraformName] = attr
+	}
+	var disableAttributes, reenableAttributes []*AttributeInfo
+	var changedAttributes []string
+	for _, attr := range r.attributes {
+		if len(attr.RequiresDisabled) == 0 {
+			continue
+		}
+		planValue, inPlan := planValues[attr.SempName]
+		stateValue, inState := stateValues[attr.SempName]
+		if attr.WriteOnly {
+			// the value is not in the plan, only its version
+			if !writeOnlyVersionChanged(attr, planAttributes, stateAttributes) {
+				continue
+			}
+		} else if inPlan == inState && reflect.DeepEqual(planValue, stateValue) {
+			continue
+		}
+		changed := false
+		for _, name := range attr.RequiresDisabled {
+			enableAttr, ok := attributesByTerraformName[name]
+			if !ok || !enabled(stateValues, enableAttr) {
+				continue
+			}
+			changed = true
+			if slices.Contains(disableAttributes, enableAttr) {
+				continue
+			}
+			disableAttributes = append(disableAttributes, enableAttr)
+			if enabled(planValues, enableAttr) {
+				reenableAttributes = append(reenableAttributes, enableAttr)
+			}
+		}
+		if changed {
+			changedAttributes = append(changedAttributes, attr.TerraformName)
+		}
+	}
+	return disableAttributes, reenableAttributes, changedAttributes, nil
+}
+
+// enableAttributesData returns the SEMP data to PATCH the enabled attributes of an object to the given value
+func (r *brokerResource) enableAttributesData(plan tftypes.Value, enableAttributes []*AttributeInfo, enabled bool) map[string]any {
+	sempData := map[string]any{}
+	planData, err := r.converter.FromTerraform(plan)
+	if err == nil {
+		planValues, _ := planData.(map[string]any)
+		for _, attr := range r.identifyingAttributes {
+			sempData[attr.SempName] = planValues[attr.SempName]
+		}
+	}
+	for _, attr := range enableAttributes {
+		sempData[attr.SempName] = enabled
+	}
+	return sempData
+}
+
+func terraformNames(attributes []*AttributeInfo) string {
+	var names []string
+	for _, attr := range attributes {
+		names = append(names, attr.TerraformName)
+	}
+	return strings.Join(names, ", ")
+}
+
 func (r *brokerResource) Schema(_ context.Context, request resource.SchemaRequest, response *resource.SchemaResponse) {
 	// Overwrite the schema version with the provider major version
 	providerMajorVersion := getProviderMajorVersion(ProviderVersion)
@@ -260,7 +515,7 @@ func (r *brokerResource) Configure(_ context.Context, request resource.Configure
 	if request.ProviderData == nil {
 		return
 	}
-	client, ok := request.ProviderData.(*semp.Client)
+	client, ok := request.ProviderData.(*brokerClient)
 	if !ok {
 		response.Diagnostics.AddError(
 			"Unexpected resource configuration",
@@ -273,7 +528,7 @@ func (r *brokerResource) Configure(_ context.Context, request resource.Configure
 
 func (r *brokerResource) Create(ctx context.Context, request resource.CreateRequest, response *resource.CreateResponse) {
 	client := r.client
-	if err := checkBrokerRequirements(ctx, client); err != nil {
+	if err := client.checkBrokerRequirements(ctx); err != nil {
 		addErrorToDiagnostics(&response.Diagnostics, "Broker check failed", err)
 		return
 	}
@@ -283,6 +538,12 @@ func (r *brokerResource) Create(ctx context.Context, request resource.CreateRequ
 		addErrorToDiagnostics(&response.Diagnostics, "Error converting data", err)
 		return
 	}
+	writeOnlyData, err := r.writeOnlyData(request.Config.Raw)
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error converting data", err)
+		return
+	}
+	maps.Copy(sempData.(map[string]any), writeOnlyData)
 
 	var sempPath string
 	method := http.MethodPut
@@ -302,7 +563,7 @@ func (r *brokerResource) Create(ctx context.Context, request resource.CreateRequ
 	}
 	jsonResponseData, err := client.RequestWithBody(ctx, method, sempPath, sempData)
 	if err != nil {
-		addErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err)
+		addSempErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err, r.attributes)
 		return
 	}
 	// Determine broker defaults as each attribute response, where request was set to null and it didn't have a default
@@ -326,11 +587,12 @@ func (r *brokerResource) Create(ctx context.Context, request resource.CreateRequ
 	response.Private.SetKey(ctx, defaults, privatData)
 	// Set the response
 	response.State.Raw = request.Plan.Raw
+	response.Diagnostics.Append(r.setIdentity(ctx, response.Identity, request.Plan.Raw)...)
 }
 
 func (r *brokerResource) Read(ctx context.Context, request resource.ReadRequest, response *resource.ReadResponse) {
 	client := r.client
-	if err := checkBrokerRequirements(ctx, client); err != nil {
+	if err := client.checkBrokerRequirements(ctx); err != nil {
 		addErrorToDiagnostics(&response.Diagnostics, "Broker check failed", err)
 		return
 	}
@@ -345,7 +607,7 @@ func (r *brokerResource) Read(ctx context.Context, request resource.ReadRequest,
 			tflog.Info(ctx, fmt.Sprintf("Detected missing resource %v, removing from state", sempPath))
 			response.State.RemoveResource(ctx)
 		} else {
-			addErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err)
+			addSempErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err, nil)
 		}
 		return
 	}
@@ -380,11 +642,12 @@ func (r *brokerResource) Read(ctx context.Context, request resource.ReadRequest,
 		return
 	}
 	response.State.Raw = responseData
+	response.Diagnostics.Append(r.setIdentity(ctx, response.Identity, responseData)...)
 }
 
 func (r *brokerResource) Update(ctx context.Context, request resource.UpdateRequest, response *resource.UpdateResponse) {
 	client := r.client
-	if err := checkBrokerRequirements(ctx, client); err != nil {
+	if err := client.checkBrokerRequirements(ctx); err != nil {
 		addErrorToDiagnostics(&response.Diagnostics, "Broker check failed", err)
 		return
 	}
@@ -393,20 +656,87 @@ func (r *brokerResource) Update(ctx context.Context, request resource.UpdateRequ
 		addErrorToDiagnostics(&response.Diagnostics, "Error converting data", err)
 		return
 	}
+	writeOnlyData, err := r.writeOnlyData(request.Config.Raw)
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error converting data", err)
+		return
+	}
+	// PUT sends the whole object, including the write-only values
+	maps.Copy(sempData.(map[string]any), writeOnlyData)
 	sempPath, err := resolveSempPath(r.pathTemplate, r.identifyingAttributes, request.Plan.Raw)
 	if err != nil {
 		addErrorToDiagnostics(&response.Diagnostics, "Error generating SEMP path", err)
 		return
 	}
+	defaultsJson, diags := request.Private.GetKey(ctx, defaults)
+	if diags.HasError() {
+		response.Diagnostics.Append(diags...)
+		return
+	}
 	method := http.MethodPut
 	if r.objectType == SingletonObject {
 		method = http.MethodPatch
+	} else if client.updateMode == updateModePatch {
+		patchData, fallbackReason, err := r.changedSempData(request.Plan.Raw, request.State.Raw, defaultsJson, writeOnlyData)
+		if err != nil {
+			addErrorToDiagnostics(&response.Diagnostics, "Error converting data", err)
+			return
+		}
+		if fallbackReason == "" {
+			method = http.MethodPatch
+			sempData = patchData
+		} else {
+			tflog.Info(ctx, fmt.Sprintf("Update: using PUT instead of PATCH for %v, %v", sempPath, fallbackReason))
+		}
+	}
+	// Some attributes can only be changed while the object is disabled: disable it first, apply the change with the
+	//   object still disabled and restore the enabled attributes afterwards
+	disableAttributes, reenableAttributes, changedAttributes, err := r.enableAttributesToToggle(request.Plan.Raw, request.State.Raw, defaultsJson)
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error converting data", err)
+		return
+	}
+	if len(disableAttributes) > 0 {
+		tflog.Info(ctx, fmt.Sprintf("Update: disabling %v of %v to change %v", terraformNames(disableAttributes), sempPath, strings.Join(changedAttributes, ", ")))
+		_, err = client.RequestWithBody(ctx, http.MethodPatch, sempPath, r.enableAttributesData(request.Plan.Raw, disableAttributes, false))
+		if err != nil {
+			addSempErrorToDiagnostics(&response.Diagnostics, fmt.Sprintf("Unable to disable %v before changing %v", terraformNames(disableAttributes), strings.Join(changedAttributes, ", ")), err, nil)
+			return
+		}
+		if data, ok := sempData.(map[string]any); ok {
+			for _, attr := range disableAttributes {
+				data[attr.SempName] = false
+			}
+		}
 	}
 	jsonResponseData, err := client.RequestWithBody(ctx, method, sempPath, sempData)
 	if err != nil {
-		addErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err)
+		addSempErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err, r.attributes)
+		if len(disableAttributes) > 0 {
+			// best effort to leave the object enabled as it was before the update
+			_, restoreErr := client.RequestWithBody(ctx, http.MethodPatch, sempPath, r.enableAttributesData(request.Plan.Raw, disableAttributes, true))
+			if restoreErr != nil {
+				addSempErrorToDiagnostics(&response.Diagnostics, fmt.Sprintf("Unable to re-enable %v after failed update, the object remains disabled", terraformNames(disableAttributes)), restoreErr, nil)
+			} else {
+				response.Diagnostics.AddWarning(
+					fmt.Sprintf("Re-enabled %v after failed update", terraformNames(disableAttributes)),
+					fmt.Sprintf("%v of %v were temporarily disabled to change %v. The update failed and they have been restored.", terraformNames(disableAttributes), sempPath, strings.Join(changedAttributes, ", ")))
+			}
+		}
 		return
 	}
+	if len(disableAttributes) > 0 {
+		if len(reenableAttributes) > 0 {
+			jsonResponseData, err = client.RequestWithBody(ctx, http.MethodPatch, sempPath, r.enableAttributesData(request.Plan.Raw, reenableAttributes, true))
+			if err != nil {
+				addSempErrorToDiagnostics(&response.Diagnostics, fmt.Sprintf("Unable to re-enable %v after changing %v, the object remains disabled", terraformNames(reenableAttributes), strings.Join(changedAttributes, ", ")), err, nil)
+				return
+			}
+		}
+		response.Diagnostics.AddWarning(
+			fmt.Sprintf("Temporarily disabled %v to apply changes", terraformNames(disableAttributes)),
+			fmt.Sprintf("%v of %v can only be changed while %v is disabled. It was disabled to apply the change and the enabled attributes have been restored as configured.", strings.Join(changedAttributes, ", "), sempPath, terraformNames(disableAttributes)))
+	}
 	// Determine broker defaults as each attribute response, where request was set to null and it didn't have a default
 	//   then store it as private data
 	tfResponseData, err := r.converter.ToTerraform(jsonResponseData)
@@ -428,11 +758,12 @@ func (r *brokerResource) Update(ctx context.Context, request resource.UpdateRequ
 	response.Private.SetKey(ctx, defaults, privatData)
 	// Set the response
 	response.State.Raw = request.Plan.Raw
+	response.Diagnostics.Append(r.setIdentity(ctx, response.Identity, request.Plan.Raw)...)
 }
 
 func (r *brokerResource) Delete(ctx context.Context, request resource.DeleteRequest, response *resource.DeleteResponse) {
 	client := r.client
-	if err := checkBrokerRequirements(ctx, client); err != nil {
+	if err := client.checkBrokerRequirements(ctx); err != nil {
 		addErrorToDiagnostics(&response.Diagnostics, "Broker check failed", err)
 		return
 	}
@@ -462,7 +793,7 @@ func (r *brokerResource) Delete(ctx context.Context, request resource.DeleteRequ
 	_, err = client.RequestWithoutBody(ctx, http.MethodDelete, path)
 	if err != nil {
 		if !errors.Is(err, semp.ErrResourceNotFound) {
-			addErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err)
+			addSempErrorToDiagnostics(&response.Diagnostics, "SEMP call failed", err, nil)
 			return
 		}
 		tflog.Info(ctx, fmt.Sprintf("Detected object %s, \"%s\" was already missing from the broker, removing from state", r.terraformName, toId(path)))
@@ -470,8 +801,61 @@ func (r *brokerResource) Delete(ctx context.Context, request resource.DeleteRequ
 	}
 }
 
-func (r *brokerResource) ImportState(_ context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+func (r *brokerResourceWithIdentity) IdentitySchema(_ context.Context, _ resource.IdentitySchemaRequest, response *resource.IdentitySchemaResponse) {
+	attributes := map[string]identityschema.Attribute{}
+	for _, attr := range r.identifyingAttributes {
+		attributes[attr.TerraformName] = identityschema.StringAttribute{
+			Description:       attr.MarkdownDescription,
+			RequiredForImport: true,
+		}
+	}
+	response.IdentitySchema = identityschema.Schema{Attributes: attributes}
+}
 
+// setIdentity sets the resource identity, if the resource has one, to the identifying attributes of the state
+func (r *brokerResource) setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, state tftypes.Value) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if identity == nil {
+		return diags
+	}
+	stateValues := map[string]tftypes.Value{}
+	if err := state.As(&stateValues); err != nil {
+		addErrorToDiagnostics(&diags, "Error setting resource identity", err)
+		return diags
+	}
+	identityValues := map[string]tftypes.Value{}
+	for _, attr := range r.identifyingAttributes {
+		identityValues[attr.TerraformName] = stateValues[attr.TerraformName]
+	}
+	identity.Raw = tftypes.NewValue(identity.Schema.Type().TerraformType(ctx), identityValues)
+	return diags
+}
+
+func (r *brokerResource) ImportState(_ context.Context, request resource.ImportStateRequest, response *resource.ImportStateResponse) {
+	if request.ID == "" && request.Identity != nil && !request.Identity.Raw.IsNull() {
+		// Imported by resource identity rather than by import identifier
+		identityValues := map[string]tftypes.Value{}
+		if err := request.Identity.Raw.As(&identityValues); err != nil {
+			addErrorToDiagnostics(&response.Diagnostics, "invalid identity", err)
+			return
+		}
+		identifierData := map[string]any{}
+		for _, attr := range r.identifyingAttributes {
+			v, err := attr.Converter.FromTerraform(identityValues[attr.TerraformName])
+			if err != nil {
+				addErrorToDiagnostics(&response.Diagnostics, "invalid identity", err)
+				return
+			}
+			identifierData[attr.SempName] = v
+		}
+		identifierState, err := r.converter.ToTerraform(identifierData)
+		if err != nil {
+			addErrorToDiagnostics(&response.Diagnostics, "invalid identity", err)
+			return
+		}
+		response.State.Raw = identifierState
+		return
+	}
 	if len(r.identifyingAttributes) == 0 {
 		if request.ID != "" {
 			response.Diagnostics.AddError(
@@ -530,7 +914,9 @@ func (r *brokerResource) addIdentifierErrorToDiagnostics(diags *diag.Diagnostics
 }
 
 func (r *brokerResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
-	return nil
+	return []resource.ConfigValidator{
+		attributeRelationshipValidator{attributes: r.attributes},
+	}
 }
 
 func (r *brokerResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
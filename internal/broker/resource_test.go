@@ -0,0 +1,233 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestChangedSempData(t *testing.T) {
+	stringConverter := SimpleConverter[string]{TerraformType: tftypes.String}
+	r := brokerResource(newBrokerResource(EntityInputs{
+		TerraformName: "msg_vpn_test",
+		PathTemplate:  "/msgVpns/{msgVpnName}/tests/{testName}",
+		Attributes: []*AttributeInfo{
+			{BaseType: String, SempName: "msgVpnName", TerraformName: "msg_vpn_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "testName", TerraformName: "test_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "description", TerraformName: "description", TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: Int64, SempName: "maxCount", TerraformName: "max_count", TerraformType: tftypes.Number, Converter: IntegerConverter{}},
+			{BaseType: String, SempName: "username", TerraformName: "username", Requires: []string{"password"}, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "password", TerraformName: "password", Sensitive: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "status", TerraformName: "status", ReadOnly: true, TerraformType: tftypes.String, Converter: stringConverter},
+		},
+	}))
+	objectType := r.converter.terraformType
+	value := func(description, maxCount, username, password, passwordVersion any) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"msg_vpn_name":        tftypes.NewValue(tftypes.String, "vpn"),
+			"test_name":           tftypes.NewValue(tftypes.String, "test"),
+			"description":         tftypes.NewValue(tftypes.String, description),
+			"max_count":           tftypes.NewValue(tftypes.Number, maxCount),
+			"username":            tftypes.NewValue(tftypes.String, username),
+			"password":            tftypes.NewValue(tftypes.String, password),
+			"password_wo":         tftypes.NewValue(tftypes.String, nil),
+			"password_wo_version": tftypes.NewValue(tftypes.Number, passwordVersion),
+			"status":              tftypes.NewValue(tftypes.String, nil),
+		})
+	}
+	identifiers := map[string]any{"msgVpnName": "vpn", "testName": "test"}
+	with := func(values map[string]any) map[string]any {
+		result := map[string]any{}
+		for k, v := range identifiers {
+			result[k] = v
+		}
+		for k, v := range values {
+			result[k] = v
+		}
+		return result
+	}
+	tests := []struct {
+		name         string
+		plan         tftypes.Value
+		state        tftypes.Value
+		defaults     string
+		writeOnly    map[string]any
+		want         map[string]any
+		wantFallback bool
+	}{
+		{"Unchanged", value("a", 10, nil, nil, nil), value("a", 10, nil, nil, nil), "", nil, identifiers, false},
+		{"Changed", value("b", 10, nil, nil, nil), value("a", 10, nil, nil, nil), "", nil, with(map[string]any{"description": "b"}), false},
+		{"Added", value("a", 20, nil, nil, nil), value("a", nil, nil, nil, nil), "", nil, with(map[string]any{"maxCount": int64(20)}), false},
+		{"RequiredSentTogether", value("a", 10, "user", "pass", nil), value("a", 10, "other", "pass", nil), "", nil, with(map[string]any{"username": "user", "password": "pass"}), false},
+		{"RemovedResetToBrokerDefault", value(nil, 10, nil, nil, nil), value("a", 10, nil, nil, nil), `{"description":"broker default"}`, nil, with(map[string]any{"description": "broker default"}), false},
+		{"RemovedLargeIntegerDefault", value("a", nil, nil, nil, nil), value("a", 10, nil, nil, nil), `{"maxCount":9007199254740993}`, nil, with(map[string]any{"maxCount": json.Number("9007199254740993")}), false},
+		{"WriteOnlyUnchanged", value("a", 10, nil, nil, 1), value("a", 10, nil, nil, 1), "", map[string]any{"password": "secret"}, identifiers, false},
+		{"WriteOnlyVersionChanged", value("a", 10, nil, nil, 2), value("a", 10, nil, nil, 1), "", map[string]any{"password": "secret"}, with(map[string]any{"password": "secret"}), false},
+		{"WriteOnlyRequiredSentTogether", value("a", 10, "user", nil, 1), value("a", 10, "other", nil, 1), "", map[string]any{"password": "secret"}, with(map[string]any{"username": "user", "password": "secret"}), false},
+		{"WriteOnlyReplacesStateValue", value("a", 10, nil, nil, nil), value("a", 10, nil, "pass", nil), "{}", map[string]any{"password": "secret"}, with(map[string]any{"password": "secret"}), false},
+		{"RemovedWithoutBrokerDefault", value(nil, 10, nil, nil, nil), value("a", 10, nil, nil, nil), "{}", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var defaultsJson []byte
+			if tt.defaults != "" {
+				defaultsJson = []byte(tt.defaults)
+			}
+			got, fallbackReason, err := r.changedSempData(tt.plan, tt.state, defaultsJson, tt.writeOnly)
+			if err != nil {
+				t.Fatalf("changedSempData() error = %v", err)
+			}
+			if (fallbackReason != "") != tt.wantFallback {
+				t.Fatalf("changedSempData() fallback reason = %q, want fallback %v", fallbackReason, tt.wantFallback)
+			}
+			if !tt.wantFallback && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changedSempData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnableAttributesToToggle(t *testing.T) {
+	stringConverter := SimpleConverter[string]{TerraformType: tftypes.String}
+	boolConverter := SimpleConverter[bool]{TerraformType: tftypes.Bool}
+	inputs := EntityInputs{
+		TerraformName: "msg_vpn_test",
+		PathTemplate:  "/msgVpns/{msgVpnName}/tests/{testName}",
+		Attributes: []*AttributeInfo{
+			{BaseType: String, SempName: "msgVpnName", TerraformName: "msg_vpn_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "testName", TerraformName: "test_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "accessType", TerraformName: "access_type", MarkdownDescription: "The access type.\n\nModifying this attribute while the object (or the relevant part of the object) is administratively enabled may be service impacting as egress_enabled will be temporarily set to false to apply the change.", TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "owner", TerraformName: "owner", MarkdownDescription: "The owner.\n\nModifying this attribute while the object (or the relevant part of the object) is administratively enabled may be service impacting as egress_enabled and ingress_enabled will be temporarily set to false to apply the change.", TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: Bool, SempName: "egressEnabled", TerraformName: "egress_enabled", TerraformType: tftypes.Bool, Converter: boolConverter, Default: true},
+			{BaseType: Bool, SempName: "ingressEnabled", TerraformName: "ingress_enabled", TerraformType: tftypes.Bool, Converter: boolConverter},
+		},
+	}
+	addRequiresDisabled(inputs)
+	if got, want := inputs.Attributes[3].RequiresDisabled, []string{"egress_enabled", "ingress_enabled"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("addRequiresDisabled() = %v, want %v", got, want)
+	}
+	r := brokerResource(newBrokerResource(inputs))
+	objectType := r.converter.terraformType
+	value := func(accessType, owner, egressEnabled, ingressEnabled any) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"msg_vpn_name":    tftypes.NewValue(tftypes.String, "vpn"),
+			"test_name":       tftypes.NewValue(tftypes.String, "test"),
+			"access_type":     tftypes.NewValue(tftypes.String, accessType),
+			"owner":           tftypes.NewValue(tftypes.String, owner),
+			"egress_enabled":  tftypes.NewValue(tftypes.Bool, egressEnabled),
+			"ingress_enabled": tftypes.NewValue(tftypes.Bool, ingressEnabled),
+		})
+	}
+	tests := []struct {
+		name         string
+		plan         tftypes.Value
+		state        tftypes.Value
+		defaults     string
+		wantDisable  string
+		wantReenable string
+		wantChanged  []string
+	}{
+		{"Unchanged", value("exclusive", "a", true, true), value("exclusive", "a", true, true), "", "", "", nil},
+		{"AlreadyDisabled", value("non-exclusive", "a", false, true), value("exclusive", "a", false, true), "", "", "", nil},
+		{"Changed", value("non-exclusive", "a", true, true), value("exclusive", "a", true, true), "", "egress_enabled", "egress_enabled", []string{"access_type"}},
+		{"ChangedAndDisabledInPlan", value("non-exclusive", "a", false, true), value("exclusive", "a", true, true), "", "egress_enabled", "", []string{"access_type"}},
+		{"Removed", value(nil, "a", true, true), value("exclusive", "a", true, true), "", "egress_enabled", "egress_enabled", []string{"access_type"}},
+		{"MultipleChanged", value("non-exclusive", "b", true, true), value("exclusive", "a", true, true), "", "egress_enabled, ingress_enabled", "egress_enabled, ingress_enabled", []string{"access_type", "owner"}},
+		{"EnabledByDefault", value("non-exclusive", "a", nil, true), value("exclusive", "a", nil, true), "", "egress_enabled", "egress_enabled", []string{"access_type"}},
+		{"EnabledByBrokerDefault", value("exclusive", "b", false, nil), value("exclusive", "a", false, nil), `{"ingressEnabled":true}`, "ingress_enabled", "ingress_enabled", []string{"owner"}},
+		{"DisabledByBrokerDefault", value("exclusive", "b", false, nil), value("exclusive", "a", false, nil), `{"ingressEnabled":false}`, "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var defaultsJson []byte
+			if tt.defaults != "" {
+				defaultsJson = []byte(tt.defaults)
+			}
+			disable, reenable, changed, err := r.enableAttributesToToggle(tt.plan, tt.state, defaultsJson)
+			if err != nil {
+				t.Fatalf("enableAttributesToToggle() error = %v", err)
+			}
+			if terraformNames(disable) != tt.wantDisable {
+				t.Errorf("enableAttributesToToggle() disable = %q, want %q", terraformNames(disable), tt.wantDisable)
+			}
+			if terraformNames(reenable) != tt.wantReenable {
+				t.Errorf("enableAttributesToToggle() reenable = %q, want %q", terraformNames(reenable), tt.wantReenable)
+			}
+			if !reflect.DeepEqual(changed, tt.wantChanged) {
+				t.Errorf("enableAttributesToToggle() changed = %v, want %v", changed, tt.wantChanged)
+			}
+		})
+	}
+	wantData := map[string]any{"msgVpnName": "vpn", "testName": "test", "egressEnabled": false}
+	if data := r.enableAttributesData(value("exclusive", "a", true, true), r.attributes[4:5], false); !reflect.DeepEqual(data, wantData) {
+		t.Errorf("enableAttributesData() = %v, want %v", data, wantData)
+	}
+}
+
+func TestImportStateByIdentity(t *testing.T) {
+	stringConverter := SimpleConverter[string]{TerraformType: tftypes.String}
+	newResource := newBrokerResourceClosure(newBrokerResource(EntityInputs{
+		TerraformName: "msg_vpn_test",
+		PathTemplate:  "/msgVpns/{msgVpnName}/tests/{testName}",
+		Attributes: []*AttributeInfo{
+			{BaseType: String, SempName: "msgVpnName", TerraformName: "msg_vpn_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "testName", TerraformName: "test_name", Identifying: true, TerraformType: tftypes.String, Converter: stringConverter},
+			{BaseType: String, SempName: "description", TerraformName: "description", TerraformType: tftypes.String, Converter: stringConverter},
+		},
+	}))
+	r, ok := newResource().(*brokerResourceWithIdentity)
+	if !ok {
+		t.Fatalf("resource with identifying attributes has no identity")
+	}
+	if _, ok := newBrokerResourceClosure(newBrokerResource(EntityInputs{TerraformName: "broker", PathTemplate: "/"}))().(resource.ResourceWithIdentity); ok {
+		t.Errorf("broker resource without identifying attributes has an identity")
+	}
+	ctx := context.Background()
+	identitySchema := &resource.IdentitySchemaResponse{}
+	r.IdentitySchema(ctx, resource.IdentitySchemaRequest{}, identitySchema)
+	identity := &tfsdk.ResourceIdentity{
+		Schema: identitySchema.IdentitySchema,
+		Raw: tftypes.NewValue(identitySchema.IdentitySchema.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"msg_vpn_name": tftypes.NewValue(tftypes.String, "vpn"),
+			"test_name":    tftypes.NewValue(tftypes.String, "a/b"),
+		}),
+	}
+	response := &resource.ImportStateResponse{}
+	r.ImportState(ctx, resource.ImportStateRequest{Identity: identity}, response)
+	if response.Diagnostics.HasError() {
+		t.Fatalf("ImportState() diagnostics: %v", response.Diagnostics)
+	}
+	state := map[string]tftypes.Value{}
+	if err := response.State.Raw.As(&state); err != nil {
+		t.Fatal(err)
+	}
+	if !state["test_name"].Equal(tftypes.NewValue(tftypes.String, "a/b")) || !state["description"].IsNull() {
+		t.Errorf("ImportState() state = %v, want the identifiers of the identity", state)
+	}
+
+	newIdentity := &tfsdk.ResourceIdentity{Schema: identitySchema.IdentitySchema}
+	if diags := r.setIdentity(ctx, newIdentity, response.State.Raw); diags.HasError() || !newIdentity.Raw.Equal(identity.Raw) {
+		t.Errorf("setIdentity() = %v, %v, want %v", newIdentity.Raw, diags, identity.Raw)
+	}
+}
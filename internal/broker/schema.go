@@ -19,10 +19,12 @@ package broker
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/list"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
@@ -30,6 +32,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
@@ -39,12 +42,25 @@ var Entities []EntityInputs
 
 func RegisterDataSource(inputs EntityInputs) {
 	DataSources = append(DataSources, newBrokerDataSourceGenerator(inputs))
+	// Objects in a collection can also be listed
+	if listDataSource, ok := newBrokerListDataSource(inputs); ok {
+		DataSources = append(DataSources, newBrokerListDataSourceClosure(listDataSource))
+	}
 }
 
 var Resources []func() resource.Resource
 
+var ListResources []func() list.ListResource
+
 func RegisterResource(inputs EntityInputs) {
-	Resources = append(Resources, newBrokerResourceGenerator(inputs))
+	addAttributeReferences(inputs)
+	addRequiresDisabled(inputs)
+	entity := newBrokerResource(inputs)
+	Resources = append(Resources, newBrokerResourceClosure(entity))
+	// Objects in a collection can be discovered with terraform query
+	if listResource, ok := newBrokerListResource(entity); ok {
+		ListResources = append(ListResources, newBrokerListResourceClosure(listResource))
+	}
 	Entities = append(Entities, inputs)
 }
 
@@ -113,8 +129,9 @@ func terraformAttributeMap(attributes []*AttributeInfo, isResource bool, require
 				Optional:            !attr.Required && isResource,
 				Computed:            !attr.Identifying && !isResource,
 				Sensitive:           attr.Sensitive,
+				WriteOnly:           attr.WriteOnly,
 				DeprecationMessage:  deprecationMessage,
-				Validators:          attr.StringValidators,
+				Validators:          withoutRelationshipValidators(attr.StringValidators),
 				PlanModifiers:       modifiers[planmodifier.String](attrRequiresReplace, stringplanmodifier.RequiresReplace),
 			}
 		case Int64:
@@ -125,8 +142,9 @@ func terraformAttributeMap(attributes []*AttributeInfo, isResource bool, require
 				Optional:            !attr.Required && isResource,
 				Computed:            !attr.Identifying && !isResource,
 				Sensitive:           attr.Sensitive,
+				WriteOnly:           attr.WriteOnly,
 				DeprecationMessage:  deprecationMessage,
-				Validators:          attr.Int64Validators,
+				Validators:          withoutRelationshipValidators(attr.Int64Validators),
 				PlanModifiers:       modifiers[planmodifier.Int64](attrRequiresReplace, int64planmodifier.RequiresReplace),
 			}
 		case Bool:
@@ -137,8 +155,9 @@ func terraformAttributeMap(attributes []*AttributeInfo, isResource bool, require
 				Optional:            !attr.Required && isResource,
 				Computed:            !attr.Identifying && !isResource,
 				Sensitive:           attr.Sensitive,
+				WriteOnly:           attr.WriteOnly,
 				DeprecationMessage:  deprecationMessage,
-				Validators:          attr.BoolValidators,
+				Validators:          withoutRelationshipValidators(attr.BoolValidators),
 				PlanModifiers:       modifiers[planmodifier.Bool](attrRequiresReplace, boolplanmodifier.RequiresReplace),
 			}
 		case Struct:
@@ -158,6 +177,8 @@ func terraformAttributeMap(attributes []*AttributeInfo, isResource bool, require
 	return tfAttributes
 }
 
+const unsupportedResourceWarning = "> This resource is not supported in production by Solace in this version, see [provider limitations](https://registry.terraform.io/providers/SolaceProducts/solacebroker/latest/docs#limitations).\n\n"
+
 type EntityInputs struct {
 	TerraformName       string
 	Description         string
@@ -170,8 +191,59 @@ type EntityInputs struct {
 	Attributes          []*AttributeInfo
 }
 
+const (
+	writeOnlySuffix        = "_wo"
+	writeOnlyVersionSuffix = "_wo_version"
+)
+
+// withWriteOnlyAttributes adds a write-only alternative and its version to each sensitive attribute of a resource, so
+// that secrets can be kept out of the state with Terraform 1.11 or later. As write-only values are not part of the
+// plan, a changed value is only sent to the broker when its version changes.
+func withWriteOnlyAttributes(attributes []*AttributeInfo) []*AttributeInfo {
+	result := slices.Clone(attributes)
+	for _, attr := range attributes {
+		if !attr.Sensitive || attr.BaseType != String {
+			continue
+		}
+		writeOnlyName := attr.TerraformName + writeOnlySuffix
+		result = append(result,
+			&AttributeInfo{
+				BaseType:            String,
+				SempName:            attr.SempName,
+				TerraformName:       writeOnlyName,
+				MarkdownDescription: fmt.Sprintf("Write-only alternative to `%s`, which is never stored in the Terraform state. Requires Terraform 1.11 or later. As write-only values are not part of the plan, change `%s` to update the value on the broker. %s", attr.TerraformName, attr.TerraformName+writeOnlyVersionSuffix, attr.MarkdownDescription),
+				Sensitive:           true,
+				WriteOnly:           true,
+				Deprecated:          attr.Deprecated,
+				Requires:            attr.Requires,
+				ConflictsWith:       append([]string{attr.TerraformName}, attr.ConflictsWith...),
+				RequiresDisabled:    attr.RequiresDisabled,
+				Type:                attr.Type,
+				TerraformType:       attr.TerraformType,
+				Converter:           attr.Converter,
+				StringValidators:    attr.StringValidators,
+			},
+			&AttributeInfo{
+				BaseType:            Int64,
+				TerraformName:       attr.TerraformName + writeOnlyVersionSuffix,
+				MarkdownDescription: fmt.Sprintf("The version of `%s`. Change it to send a new value of `%s` to the broker.", writeOnlyName, writeOnlyName),
+				WriteOnlyVersion:    true,
+				RequiresReplace:     attr.RequiresReplace,
+				Deprecated:          attr.Deprecated,
+				Requires:            []string{writeOnlyName},
+				Type:                types.Int64Type,
+				TerraformType:       tftypes.Number,
+				Converter:           IntegerConverter{},
+			})
+	}
+	return result
+}
+
 func newBrokerEntity(inputs EntityInputs, isResource bool) brokerEntity[schema.Schema] {
 	addObjectConverters(inputs.Attributes)
+	if isResource {
+		inputs.Attributes = withWriteOnlyAttributes(inputs.Attributes)
+	}
 	tfAttributes := terraformAttributeMap(inputs.Attributes, isResource, inputs.ObjectType == ReplaceOnlyObject)
 	var identifyingAttributes []*AttributeInfo
 	identifyingAttributesMap := map[string]string{}
@@ -188,10 +260,10 @@ func newBrokerEntity(inputs EntityInputs, isResource bool) brokerEntity[schema.S
 		jIndex := strings.Index(inputs.PathTemplate, "{"+jAttr.SempName+"}")
 		return iIndex < jIndex
 	})
-	unsupportedResourceWarning := ""
+	unsupportedWarning := ""
 	// Add unsupported warning for any resource not contained within a message vpn
 	if !strings.HasPrefix(inputs.TerraformName, "msg_vpn") {
-		unsupportedResourceWarning = "> This resource is not supported in production by Solace in this version, see [provider limitations](https://registry.terraform.io/providers/SolaceProducts/solacebroker/latest/docs#limitations).\n\n"
+		unsupportedWarning = unsupportedResourceWarning
 	}
 	identifierInfo := ""
 	if isResource {
@@ -219,7 +291,7 @@ func newBrokerEntity(inputs EntityInputs, isResource bool) brokerEntity[schema.S
 	s := schema.Schema{
 		Attributes:          tfAttributes,
 		Description:         inputs.Description,
-		MarkdownDescription: unsupportedResourceWarning + inputs.MarkdownDescription + identifierInfo,
+		MarkdownDescription: unsupportedWarning + inputs.MarkdownDescription + identifierInfo,
 		DeprecationMessage:  inputs.DeprecationMessage,
 		Version:             inputs.Version, // This will be replaced by the major version from ProviderVersion in resource.go
 	}
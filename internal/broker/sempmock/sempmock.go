@@ -0,0 +1,743 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sempmock provides an in-process stand-in for the SEMP v2 config API of a broker, so that the provider and
+// the config generator can be tested without a broker. It is driven by the entities registered in broker.Entities:
+// objects can be created, read, updated and deleted at their path templates, collections are paged and filtered with
+// where and select, and unknown paths or objects are reported with the same error envelopes as a broker. Latency and
+// errors can be injected.
+package sempmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"terraform-provider-solacebroker/internal/broker"
+	_ "terraform-provider-solacebroker/internal/broker/generated"
+)
+
+// SEMP error statuses and codes returned by the mock broker
+const (
+	StatusNotFound         = "NOT_FOUND"
+	StatusInvalidPath      = "INVALID_PATH"
+	StatusAlreadyExists    = "ALREADY_EXISTS"
+	StatusNotAllowed       = "NOT_ALLOWED"
+	StatusInvalidParameter = "INVALID_PARAMETER"
+	StatusMissingParameter = "MISSING_PARAMETER"
+	StatusUnauthorized     = "UNAUTHORIZED"
+)
+
+var errorCodes = map[string]int{
+	StatusNotFound:         6,
+	StatusMissingParameter: 7,
+	StatusUnauthorized:     8,
+	StatusNotAllowed:       9,
+	StatusAlreadyExists:    10,
+	StatusInvalidParameter: 11,
+	StatusInvalidPath:      21,
+}
+
+const DefaultPageSize = 10
+
+type Option func(*Broker)
+
+// BasicAuth makes the mock broker reject requests that don't use these credentials. By default any or no
+// credentials are accepted.
+func BasicAuth(username, password string) Option {
+	return func(b *Broker) {
+		b.username = username
+		b.password = password
+	}
+}
+
+// PageSize sets the number of objects returned per page of a collection if the request doesn't specify a count.
+func PageSize(pageSize int) Option {
+	return func(b *Broker) {
+		b.pageSize = pageSize
+	}
+}
+
+// Latency delays every response by the given duration.
+func Latency(latency time.Duration) Option {
+	return func(b *Broker) {
+		b.latency = latency
+	}
+}
+
+// Entities sets the entities served by the mock broker instead of broker.Entities.
+func Entities(entities []broker.EntityInputs) Option {
+	return func(b *Broker) {
+		b.entityInputs = entities
+	}
+}
+
+// Fault is an injected failure or delay for the requests it matches.
+type Fault struct {
+	Method      string        // The HTTP method to match, empty matches any method
+	Path        string        // The path below the SEMP base path to match, a trailing * matches a prefix, empty matches any path
+	Latency     time.Duration // Additional delay of the response
+	HTTPStatus  int           // If set, the request fails with this HTTP status
+	Status      string        // The SEMP error status reported with HTTPStatus; if empty the response has no SEMP envelope
+	Description string        // The error description or response body
+	Count       int           // The number of requests to apply the fault to, 0 for all requests
+}
+
+func (f *Fault) matches(method string, path string) bool {
+	if f.Method != "" && f.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(f.Path, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return f.Path == "" || f.Path == path
+}
+
+// Broker is a SEMP v2 config API stand-in served by an httptest.Server. Use URL as the broker URL of the provider
+// or the generator.
+type Broker struct {
+	*httptest.Server
+	basePath     string
+	entityInputs []broker.EntityInputs
+	entities     []*entity
+	username     string
+	password     string
+	pageSize     int
+	latency      time.Duration
+	lock         sync.Mutex
+	objects      map[string]*object
+	faults       []*Fault
+	requests     []string
+}
+
+type entity struct {
+	broker.EntityInputs
+	objectPattern     *regexp.Regexp
+	collectionPattern *regexp.Regexp
+	identifiers       []string
+	parentTemplate    string
+}
+
+type object struct {
+	entity      *entity
+	identifiers []string
+	data        map[string]any
+}
+
+// New starts a mock broker serving the SEMP API version and entities registered with the broker package.
+func New(options ...Option) *Broker {
+	b := &Broker{
+		basePath:     broker.SempDetail.BasePath,
+		entityInputs: broker.Entities,
+		pageSize:     DefaultPageSize,
+		objects:      map[string]*object{},
+	}
+	for _, o := range options {
+		o(b)
+	}
+	for _, inputs := range b.entityInputs {
+		b.entities = append(b.entities, newEntity(inputs))
+	}
+	b.Server = httptest.NewServer(http.HandlerFunc(b.serveHTTP))
+	return b
+}
+
+var templateVariable = regexp.MustCompile(`{[^{}]*}`)
+
+func newEntity(inputs broker.EntityInputs) *entity {
+	e := &entity{EntityInputs: inputs}
+	for _, match := range templateVariable.FindAllString(inputs.PathTemplate, -1) {
+		e.identifiers = append(e.identifiers, strings.Trim(match, "{}"))
+	}
+	e.objectPattern = templatePattern(inputs.PathTemplate)
+	sections := strings.Split(inputs.PathTemplate, "/")
+	if strings.Contains(sections[len(sections)-1], "{") {
+		collectionTemplate := strings.Join(sections[:len(sections)-1], "/")
+		e.collectionPattern = templatePattern(collectionTemplate)
+		if len(sections) > 2 {
+			e.parentTemplate = strings.Join(sections[:len(sections)-2], "/")
+		}
+	}
+	return e
+}
+
+// templatePattern creates a regular expression matching escaped paths of a path template, capturing the identifiers.
+// Segments with several identifiers separate them by commas.
+func templatePattern(template string) *regexp.Regexp {
+	if template == "/" {
+		return regexp.MustCompile("^/$")
+	}
+	pattern := "^"
+	for _, section := range strings.Split(strings.TrimPrefix(template, "/"), "/") {
+		pattern += "/"
+		variables := templateVariable.FindAllStringIndex(section, -1)
+		valuePattern := "([^/]+)"
+		if len(variables) > 1 {
+			valuePattern = "([^/,]+)"
+		}
+		last := 0
+		for _, v := range variables {
+			pattern += regexp.QuoteMeta(section[last:v[0]]) + valuePattern
+			last = v[1]
+		}
+		pattern += regexp.QuoteMeta(section[last:])
+	}
+	return regexp.MustCompile(pattern + "$")
+}
+
+func (e *entity) objectPath(identifiers []string) string {
+	i := 0
+	return templateVariable.ReplaceAllStringFunc(e.PathTemplate, func(string) string {
+		value := url.PathEscape(identifiers[i])
+		i++
+		return value
+	})
+}
+
+func (e *entity) attribute(sempName string) *broker.AttributeInfo {
+	for _, attr := range e.Attributes {
+		if attr.SempName == sempName {
+			return attr
+		}
+	}
+	return nil
+}
+
+func unescapeAll(values []string) ([]string, error) {
+	var result []string
+	for _, v := range values {
+		unescaped, err := url.PathUnescape(v)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, unescaped)
+	}
+	return result, nil
+}
+
+// AddFault injects a failure or delay for the matching requests.
+func (b *Broker) AddFault(fault Fault) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.faults = append(b.faults, &fault)
+}
+
+// Requests returns the requests received so far as method and path below the SEMP base path, including the query.
+func (b *Broker) Requests() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return slices.Clone(b.requests)
+}
+
+// Object returns the attributes set on the object at path, below the SEMP base path.
+func (b *Broker) Object(path string) (map[string]any, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	e, identifiers, err := b.matchObject(path)
+	if err != nil || e == nil {
+		return nil, false
+	}
+	o, ok := b.objects[e.objectPath(identifiers)]
+	if !ok {
+		return nil, false
+	}
+	return b.objectData(o), true
+}
+
+// SetObject creates or replaces the object at path, below the SEMP base path, without checking its parent.
+func (b *Broker) SetObject(path string, data map[string]any) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	e, identifiers, err := b.matchObject(path)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		return fmt.Errorf("no entity matches path %v", path)
+	}
+	b.objects[e.objectPath(identifiers)] = &object{entity: e, identifiers: identifiers, data: data}
+	return nil
+}
+
+func (b *Broker) matchObject(path string) (*entity, []string, error) {
+	for _, e := range b.entities {
+		if match := e.objectPattern.FindStringSubmatch(path); match != nil {
+			identifiers, err := unescapeAll(match[1:])
+			return e, identifiers, err
+		}
+	}
+	return nil, nil, nil
+}
+
+func (b *Broker) matchCollection(path string) (*entity, []string, error) {
+	for _, e := range b.entities {
+		if e.collectionPattern == nil {
+			continue
+		}
+		if match := e.collectionPattern.FindStringSubmatch(path); match != nil {
+			identifiers, err := unescapeAll(match[1:])
+			return e, identifiers, err
+		}
+	}
+	return nil, nil, nil
+}
+
+func (b *Broker) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path, _ := strings.CutPrefix(r.URL.EscapedPath(), b.basePath)
+	if path == "" {
+		path = "/"
+	}
+	request := r.Method + " " + path
+	if r.URL.RawQuery != "" {
+		request += "?" + r.URL.RawQuery
+	}
+	b.lock.Lock()
+	b.requests = append(b.requests, request)
+	fault := b.takeFault(r.Method, path)
+	b.lock.Unlock()
+
+	time.Sleep(b.latency)
+	if fault != nil {
+		time.Sleep(fault.Latency)
+		if fault.HTTPStatus != 0 {
+			if fault.Status == "" {
+				w.WriteHeader(fault.HTTPStatus)
+				_, _ = w.Write([]byte(fault.Description))
+				return
+			}
+			b.writeError(w, r, fault.HTTPStatus, fault.Status, fault.Description)
+			return
+		}
+	}
+	if b.username != "" {
+		if username, password, ok := r.BasicAuth(); !ok || username != b.username || password != b.password {
+			b.writeError(w, r, http.StatusUnauthorized, StatusUnauthorized, "Unauthorized")
+			return
+		}
+	}
+	if !strings.HasPrefix(r.URL.EscapedPath(), b.basePath) {
+		b.writeError(w, r, http.StatusBadRequest, StatusInvalidPath, fmt.Sprintf("Invalid path %v", r.URL.Path))
+		return
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if path == "/about/api" && r.Method == http.MethodGet {
+		b.writeData(w, r, map[string]any{
+			"platform":    broker.SempDetail.Platform,
+			"sempVersion": broker.SempDetail.SempVersion,
+		})
+		return
+	}
+	e, identifiers, err := b.matchObject(path)
+	if err != nil {
+		b.writeError(w, r, http.StatusBadRequest, StatusInvalidPath, err.Error())
+		return
+	}
+	if e != nil {
+		b.serveObject(w, r, e, identifiers)
+		return
+	}
+	e, identifiers, err = b.matchCollection(path)
+	if err != nil {
+		b.writeError(w, r, http.StatusBadRequest, StatusInvalidPath, err.Error())
+		return
+	}
+	if e != nil {
+		b.serveCollection(w, r, e, path, identifiers)
+		return
+	}
+	b.writeError(w, r, http.StatusBadRequest, StatusInvalidPath, fmt.Sprintf("Invalid path %v", r.URL.Path))
+}
+
+func (b *Broker) takeFault(method string, path string) *Fault {
+	for i, f := range b.faults {
+		if !f.matches(method, path) {
+			continue
+		}
+		if f.Count > 0 {
+			f.Count--
+			if f.Count == 0 {
+				b.faults = slices.Delete(b.faults, i, i+1)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+func (b *Broker) serveObject(w http.ResponseWriter, r *http.Request, e *entity, identifiers []string) {
+	path := e.objectPath(identifiers)
+	o, exists := b.objects[path]
+	if !exists && e.ObjectType == broker.SingletonObject {
+		// singletons always exist
+		o = &object{entity: e, identifiers: identifiers, data: map[string]any{}}
+		b.objects[path] = o
+		exists = true
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if !exists {
+			b.writeNotFound(w, r, e, identifiers)
+			return
+		}
+		b.writeData(w, r, b.objectData(o))
+	case http.MethodPut, http.MethodPatch:
+		data, ok := b.readBody(w, r, e)
+		if !ok {
+			return
+		}
+		if !exists {
+			if r.Method == http.MethodPatch || e.PostPathTemplate != "" {
+				b.writeNotFound(w, r, e, identifiers)
+				return
+			}
+			if !b.parentExists(w, r, e, identifiers) {
+				return
+			}
+			o = &object{entity: e, identifiers: identifiers, data: map[string]any{}}
+			b.objects[path] = o
+		}
+		if r.Method == http.MethodPut {
+			o.data = data
+		} else {
+			for k, v := range data {
+				o.data[k] = v
+			}
+		}
+		b.writeData(w, r, b.objectData(o))
+	case http.MethodDelete:
+		if e.ObjectType == broker.SingletonObject {
+			b.writeError(w, r, http.StatusBadRequest, StatusNotAllowed, fmt.Sprintf("Deleting %v is not allowed", e.TerraformName))
+			return
+		}
+		if !exists {
+			b.writeNotFound(w, r, e, identifiers)
+			return
+		}
+		b.deleteWithChildren(o)
+		b.writeMeta(w, r, http.StatusOK, nil)
+	default:
+		b.writeError(w, r, http.StatusMethodNotAllowed, StatusNotAllowed, fmt.Sprintf("Method %v is not allowed", r.Method))
+	}
+}
+
+func (b *Broker) serveCollection(w http.ResponseWriter, r *http.Request, e *entity, path string, parentIdentifiers []string) {
+	if !b.parentExists(w, r, e, parentIdentifiers) {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		conditions, err := parseWhere(r.URL.Query().Get("where"))
+		if err != nil {
+			b.writeError(w, r, http.StatusBadRequest, StatusInvalidParameter, err.Error())
+			return
+		}
+		var paths []string
+		for objectPath, o := range b.objects {
+			if o.entity == e && slices.Equal(o.identifiers[:len(parentIdentifiers)], parentIdentifiers) && conditions.match(b.objectData(o)) {
+				paths = append(paths, objectPath)
+			}
+		}
+		sort.Strings(paths)
+		count := b.pageSize
+		if c, err := strconv.Atoi(r.URL.Query().Get("count")); err == nil && c > 0 {
+			count = c
+		}
+		cursor, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		cursor = max(0, min(cursor, len(paths)))
+		end := min(cursor+count, len(paths))
+		data := []any{}
+		for _, objectPath := range paths[cursor:end] {
+			data = append(data, selectAttributes(b.objectData(b.objects[objectPath]), r.URL.Query().Get("select"), e.identifiers))
+		}
+		var paging map[string]any
+		if end < len(paths) {
+			query := url.Values{}
+			query.Set("count", strconv.Itoa(count))
+			query.Set("cursor", strconv.Itoa(end))
+			for _, name := range []string{"where", "select"} {
+				if value := r.URL.Query().Get(name); value != "" {
+					query.Set(name, value)
+				}
+			}
+			paging = map[string]any{
+				"cursorQuery": query.Get("cursor"),
+				"nextPageUri": "http://" + r.Host + b.basePath + path + "?" + query.Encode(),
+			}
+		}
+		b.writeList(w, r, data, paging)
+	case http.MethodPost:
+		if e.PostPathTemplate == "" {
+			b.writeError(w, r, http.StatusMethodNotAllowed, StatusNotAllowed, fmt.Sprintf("Method %v is not allowed", r.Method))
+			return
+		}
+		data, ok := b.readBody(w, r, e)
+		if !ok {
+			return
+		}
+		identifiers := slices.Clone(parentIdentifiers)
+		for _, name := range e.identifiers[len(parentIdentifiers):] {
+			value, ok := data[name]
+			if !ok {
+				b.writeError(w, r, http.StatusBadRequest, StatusMissingParameter, fmt.Sprintf("Missing attribute %v", name))
+				return
+			}
+			identifiers = append(identifiers, fmt.Sprint(value))
+		}
+		objectPath := e.objectPath(identifiers)
+		if _, exists := b.objects[objectPath]; exists {
+			b.writeError(w, r, http.StatusBadRequest, StatusAlreadyExists, fmt.Sprintf("Object %v already exists", objectPath))
+			return
+		}
+		o := &object{entity: e, identifiers: identifiers, data: data}
+		b.objects[objectPath] = o
+		b.writeData(w, r, b.objectData(o))
+	default:
+		b.writeError(w, r, http.StatusMethodNotAllowed, StatusNotAllowed, fmt.Sprintf("Method %v is not allowed", r.Method))
+	}
+}
+
+type condition struct {
+	attribute string
+	operator  string
+	value     string
+}
+
+type conditions []condition
+
+// parseWhere parses the comma-separated conditions of the where query parameter of a collection
+func parseWhere(where string) (conditions, error) {
+	var result conditions
+	if where == "" {
+		return result, nil
+	}
+	for _, expression := range strings.Split(where, ",") {
+		match := whereConditionPattern.FindStringSubmatch(expression)
+		if match == nil {
+			return nil, fmt.Errorf("invalid where condition %v", expression)
+		}
+		result = append(result, condition{attribute: match[1], operator: match[2], value: match[3]})
+	}
+	return result, nil
+}
+
+var whereConditionPattern = regexp.MustCompile(`^(\w+)(==|!=|<=|>=|<|>)(.*)$`)
+
+// match reports whether the object data meets all conditions. Equality conditions compare strings with * as a
+// wildcard, the others compare numbers.
+func (c conditions) match(data map[string]any) bool {
+	for _, condition := range c {
+		value, ok := data[condition.attribute]
+		if !ok {
+			return false
+		}
+		switch condition.operator {
+		case "==", "!=":
+			pattern := "^" + strings.ReplaceAll(regexp.QuoteMeta(condition.value), `\*`, ".*") + "$"
+			if regexp.MustCompile(pattern).MatchString(fmt.Sprint(value)) != (condition.operator == "==") {
+				return false
+			}
+		default:
+			actual, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+			if err != nil {
+				return false
+			}
+			expected, err := strconv.ParseFloat(condition.value, 64)
+			if err != nil {
+				return false
+			}
+			if !map[string]bool{"<": actual < expected, ">": actual > expected, "<=": actual <= expected, ">=": actual >= expected}[condition.operator] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// selectAttributes limits the object data to the comma-separated attributes of the select query parameter, keeping
+// the identifying attributes
+func selectAttributes(data map[string]any, selected string, identifiers []string) map[string]any {
+	if selected == "" {
+		return data
+	}
+	result := map[string]any{}
+	for _, name := range append(strings.Split(selected, ","), identifiers...) {
+		if value, ok := data[name]; ok {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// parentExists checks that the object containing the object or collection exists, writing a NOT_FOUND error if not.
+func (b *Broker) parentExists(w http.ResponseWriter, r *http.Request, e *entity, identifiers []string) bool {
+	if e.parentTemplate == "" {
+		return true
+	}
+	for _, parent := range b.entities {
+		if parent.PathTemplate != e.parentTemplate || parent.ObjectType == broker.SingletonObject {
+			continue
+		}
+		parentIdentifiers := identifiers[:len(parent.identifiers)]
+		if _, exists := b.objects[parent.objectPath(parentIdentifiers)]; !exists {
+			b.writeNotFound(w, r, parent, parentIdentifiers)
+			return false
+		}
+	}
+	return true
+}
+
+func (b *Broker) deleteWithChildren(o *object) {
+	for path, child := range b.objects {
+		if strings.HasPrefix(child.entity.PathTemplate, o.entity.PathTemplate+"/") &&
+			slices.Equal(child.identifiers[:len(o.identifiers)], o.identifiers) {
+			delete(b.objects, path)
+		}
+	}
+	delete(b.objects, o.entity.objectPath(o.identifiers))
+}
+
+// readBody decodes the attributes in the request body, rejecting unknown attributes like a broker
+func (b *Broker) readBody(w http.ResponseWriter, r *http.Request, e *entity) (map[string]any, bool) {
+	data := map[string]any{}
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+	if err := decoder.Decode(&data); err != nil {
+		b.writeError(w, r, http.StatusBadRequest, StatusInvalidParameter, fmt.Sprintf("Could not parse request body: %v", err))
+		return nil, false
+	}
+	for name := range data {
+		if e.attribute(name) == nil {
+			b.writeError(w, r, http.StatusBadRequest, StatusInvalidParameter, fmt.Sprintf("Unknown attribute %v", name))
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+// objectData returns the attributes of an object as a broker would: defaults for attributes that are not set, the
+// identifiers from the path and no sensitive attributes
+func (b *Broker) objectData(o *object) map[string]any {
+	data := defaultValues(o.entity.Attributes)
+	for k, v := range o.data {
+		if attr := o.entity.attribute(k); attr != nil && attr.Sensitive {
+			continue
+		}
+		data[k] = v
+	}
+	for i, name := range o.entity.identifiers {
+		var value any = o.identifiers[i]
+		if attr := o.entity.attribute(name); attr != nil && attr.BaseType == broker.Int64 {
+			value = json.Number(o.identifiers[i])
+		}
+		data[name] = value
+	}
+	return data
+}
+
+func defaultValues(attributes []*broker.AttributeInfo) map[string]any {
+	data := map[string]any{}
+	for _, attr := range attributes {
+		if attr.Sensitive {
+			continue
+		}
+		if attr.BaseType == broker.Struct {
+			if nested := defaultValues(attr.Attributes); len(nested) > 0 {
+				data[attr.SempName] = nested
+			}
+		} else if attr.Default != nil {
+			data[attr.SempName] = attr.Default
+		}
+	}
+	return data
+}
+
+func (b *Broker) writeNotFound(w http.ResponseWriter, r *http.Request, e *entity, identifiers []string) {
+	var names []string
+	for i, name := range e.identifiers[:len(identifiers)] {
+		names = append(names, fmt.Sprintf("%v %v", name, identifiers[i]))
+	}
+	b.writeError(w, r, http.StatusBadRequest, StatusNotFound, fmt.Sprintf("Could not find match for %v", strings.Join(names, ", ")))
+}
+
+func (b *Broker) writeError(w http.ResponseWriter, r *http.Request, httpStatus int, status string, description string) {
+	b.writeMeta(w, r, httpStatus, map[string]any{
+		"code":        errorCodes[status],
+		"description": description,
+		"status":      status,
+	})
+}
+
+func (b *Broker) writeMeta(w http.ResponseWriter, r *http.Request, httpStatus int, sempError map[string]any) {
+	meta := requestMeta(r, httpStatus)
+	if sempError != nil {
+		meta["error"] = sempError
+	}
+	writeJSON(w, httpStatus, map[string]any{"meta": meta})
+}
+
+func (b *Broker) writeData(w http.ResponseWriter, r *http.Request, data map[string]any) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": data,
+		"meta": requestMeta(r, http.StatusOK),
+	})
+}
+
+func (b *Broker) writeList(w http.ResponseWriter, r *http.Request, data []any, paging map[string]any) {
+	meta := requestMeta(r, http.StatusOK)
+	meta["count"] = len(data)
+	if paging != nil {
+		meta["paging"] = paging
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"data": data,
+		"meta": meta,
+	})
+}
+
+func requestMeta(r *http.Request, httpStatus int) map[string]any {
+	return map[string]any{
+		"request": map[string]any{
+			"method": r.Method,
+			"uri":    "http://" + r.Host + r.URL.RequestURI(),
+		},
+		"responseCode": httpStatus,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, httpStatus int, body map[string]any) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(body); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_, _ = w.Write(buffer.Bytes())
+}
@@ -0,0 +1,217 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sempmock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"terraform-provider-solacebroker/internal/broker"
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func newTestClient(t *testing.T, options ...Option) (*Broker, *semp.Client) {
+	b := New(options...)
+	t.Cleanup(b.Close)
+	client := semp.NewClient(b.URL+broker.SempDetail.BasePath, false, false,
+		semp.BasicAuth("admin", "admin"),
+		semp.Retries(0, 0, 0),
+		semp.RequestLimits(semp.DefaultRequestTimeout, 0))
+	return b, client
+}
+
+func TestObjectLifecycle(t *testing.T) {
+	ctx := context.Background()
+	b, client := newTestClient(t)
+	if _, err := client.RequestWithBody(ctx, http.MethodPut, "/msgVpns/test", map[string]any{"msgVpnName": "test", "enabled": true}); err != nil {
+		t.Fatalf("creating msg vpn failed: %v", err)
+	}
+	data, err := client.RequestWithBody(ctx, http.MethodPut, "/msgVpns/test/queues/q%2F1", map[string]any{"queueName": "q/1", "maxBindCount": 10})
+	if err != nil {
+		t.Fatalf("creating queue failed: %v", err)
+	}
+	if data["queueName"] != "q/1" || fmt.Sprint(data["maxBindCount"]) != "10" || data["accessType"] != "exclusive" {
+		t.Errorf("unexpected queue data %v", data)
+	}
+	data, err = client.RequestWithBody(ctx, http.MethodPatch, "/msgVpns/test/queues/q%2F1", map[string]any{"accessType": "non-exclusive"})
+	if err != nil {
+		t.Fatalf("patching queue failed: %v", err)
+	}
+	if fmt.Sprint(data["maxBindCount"]) != "10" || data["accessType"] != "non-exclusive" {
+		t.Errorf("unexpected queue data after PATCH %v", data)
+	}
+	data, err = client.RequestWithBody(ctx, http.MethodPut, "/msgVpns/test/queues/q%2F1", map[string]any{"queueName": "q/1", "owner": "me"})
+	if err != nil {
+		t.Fatalf("replacing queue failed: %v", err)
+	}
+	if fmt.Sprint(data["maxBindCount"]) == "10" || data["accessType"] != "exclusive" || data["owner"] != "me" {
+		t.Errorf("unexpected queue data after PUT %v", data)
+	}
+	if _, err := client.RequestWithBody(ctx, http.MethodPost, "/msgVpns/test/queues/q%2F1/subscriptions", map[string]any{"subscriptionTopic": "a/>"}); err != nil {
+		t.Fatalf("creating subscription failed: %v", err)
+	}
+	_, err = client.RequestWithBody(ctx, http.MethodPost, "/msgVpns/test/queues/q%2F1/subscriptions", map[string]any{"subscriptionTopic": "a/>"})
+	var sempError *semp.Error
+	if !errors.As(err, &sempError) || sempError.Status != StatusAlreadyExists {
+		t.Errorf("creating existing subscription returned %v, want ALREADY_EXISTS", err)
+	}
+	if _, err := client.RequestWithoutBody(ctx, http.MethodDelete, "/msgVpns/test/queues/q%2F1"); err != nil {
+		t.Fatalf("deleting queue failed: %v", err)
+	}
+	if _, ok := b.Object("/msgVpns/test/queues/q%2F1/subscriptions/a%2F%3E"); ok {
+		t.Errorf("subscription not deleted with its queue")
+	}
+	if _, err := client.RequestWithoutBody(ctx, http.MethodGet, "/msgVpns/test/queues/q%2F1"); !errors.Is(err, semp.ErrResourceNotFound) {
+		t.Errorf("reading deleted queue returned %v, want NOT_FOUND", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	ctx := context.Background()
+	b, client := newTestClient(t, BasicAuth("admin", "admin"))
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       map[string]any
+		wantStatus string
+	}{
+		{"MissingObject", http.MethodGet, "/msgVpns/missing", nil, StatusNotFound},
+		{"MissingParent", http.MethodPut, "/msgVpns/missing/queues/q", map[string]any{"queueName": "q"}, StatusNotFound},
+		{"InvalidPath", http.MethodGet, "/unknownObjects/x", nil, StatusInvalidPath},
+		{"UnknownAttribute", http.MethodPut, "/msgVpns/test", map[string]any{"unknownAttribute": true}, StatusInvalidParameter},
+		{"DeleteSingleton", http.MethodDelete, "/", nil, StatusNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.body != nil {
+				_, err = client.RequestWithBody(ctx, tt.method, tt.path, tt.body)
+			} else {
+				_, err = client.RequestWithoutBody(ctx, tt.method, tt.path)
+			}
+			var sempError *semp.Error
+			if !errors.As(err, &sempError) || sempError.Status != tt.wantStatus {
+				t.Errorf("request returned %v, want %v", err, tt.wantStatus)
+			}
+		})
+	}
+	unauthorized := semp.NewClient(b.URL+broker.SempDetail.BasePath, false, false, semp.BasicAuth("admin", "wrong"), semp.Retries(0, 0, 0))
+	_, err := unauthorized.RequestWithoutBody(ctx, http.MethodGet, "/about/api")
+	var sempError *semp.Error
+	if !errors.As(err, &sempError) || sempError.HTTPStatus != http.StatusUnauthorized {
+		t.Errorf("request with wrong credentials returned %v, want status 401", err)
+	}
+}
+
+func TestPaging(t *testing.T) {
+	ctx := context.Background()
+	b, client := newTestClient(t, PageSize(3))
+	if err := b.SetObject("/msgVpns/test", map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 8; i++ {
+		if err := b.SetObject(fmt.Sprintf("/msgVpns/test/queues/q%d", i), map[string]any{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := client.RequestWithoutBodyForGenerator(ctx, broker.SempDetail.BasePath, http.MethodGet, "/msgVpns/test/queues", []map[string]any{})
+	if err != nil {
+		t.Fatalf("listing queues failed: %v", err)
+	}
+	if len(results) != 8 {
+		t.Fatalf("listing queues returned %d queues, want 8", len(results))
+	}
+	for i, result := range results {
+		if result["queueName"] != fmt.Sprintf("q%d", i) {
+			t.Errorf("queue %d is %v", i, result["queueName"])
+		}
+	}
+	if requests := b.Requests(); len(requests) != 3 {
+		t.Errorf("listing queues took requests %v, want 3 pages", requests)
+	}
+	_, err = client.RequestWithoutBodyForGenerator(ctx, broker.SempDetail.BasePath, http.MethodGet, "/msgVpns/test/unknownObjects", []map[string]any{})
+	if !errors.Is(err, semp.ErrInvalidPath) {
+		t.Errorf("listing unknown objects returned %v, want INVALID_PATH", err)
+	}
+}
+
+func TestWhereAndSelect(t *testing.T) {
+	ctx := context.Background()
+	b, client := newTestClient(t, PageSize(2))
+	if err := b.SetObject("/msgVpns/test", map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"orders.a", "orders.b", "other", "orders.c"} {
+		if err := b.SetObject("/msgVpns/test/queues/"+name, map[string]any{"maxBindCount": 10 * i, "owner": "me"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	query := url.Values{"where": {"queueName==orders.*,maxBindCount>=10"}, "select": {"maxBindCount"}}
+	results, err := client.RequestWithoutBodyForGenerator(ctx, broker.SempDetail.BasePath, http.MethodGet, "/msgVpns/test/queues?"+query.Encode(), []map[string]any{})
+	if err != nil {
+		t.Fatalf("listing queues failed: %v", err)
+	}
+	var names []string
+	for _, result := range results {
+		names = append(names, fmt.Sprint(result["queueName"]))
+		if _, ok := result["owner"]; ok {
+			t.Errorf("queue %v has unselected attribute owner", result["queueName"])
+		}
+		if _, ok := result["maxBindCount"]; !ok {
+			t.Errorf("queue %v misses selected attribute maxBindCount", result["queueName"])
+		}
+	}
+	if !slices.Equal(names, []string{"orders.b", "orders.c"}) {
+		t.Errorf("listing queues returned %v, want orders.b and orders.c", names)
+	}
+	_, err = client.RequestWithoutBodyForGenerator(ctx, broker.SempDetail.BasePath, http.MethodGet, "/msgVpns/test/queues?where=queueName", []map[string]any{})
+	if !errors.Is(err, semp.ErrBadRequest) {
+		t.Errorf("listing queues with an invalid condition returned %v, want INVALID_PARAMETER", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	ctx := context.Background()
+	b, client := newTestClient(t)
+	b.AddFault(Fault{Method: http.MethodGet, Path: "/msgVpns/*", HTTPStatus: http.StatusBadRequest, Status: StatusNotAllowed, Description: "injected", Count: 1})
+	b.AddFault(Fault{Path: "/about/api", Latency: 50 * time.Millisecond})
+	if err := b.SetObject("/msgVpns/test", map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.RequestWithoutBody(ctx, http.MethodGet, "/msgVpns/test")
+	var sempError *semp.Error
+	if !errors.As(err, &sempError) || sempError.Status != StatusNotAllowed || sempError.Description != "injected" {
+		t.Errorf("first request returned %v, want injected error", err)
+	}
+	if _, err := client.RequestWithoutBody(ctx, http.MethodGet, "/msgVpns/test"); err != nil {
+		t.Errorf("second request returned %v, want no error", err)
+	}
+	start := time.Now()
+	if _, err := client.RequestWithoutBody(ctx, http.MethodGet, "/about/api"); err != nil {
+		t.Errorf("request returned %v, want no error", err)
+	}
+	if time.Since(start) < 50*time.Millisecond {
+		t.Errorf("request was not delayed")
+	}
+}
@@ -0,0 +1,214 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &sempTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &sempTokenEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithRenew     = &sempTokenEphemeralResource{}
+)
+
+// sempTokenRenewMargin is how long before it expires a token is renewed, at most half of its lifetime
+const sempTokenRenewMargin = time.Minute
+
+// sempTokenPrivateKey is the private data key of the token request and the latest token
+const sempTokenPrivateKey = "semp_token"
+
+var EphemeralResources = []func() ephemeral.EphemeralResource{
+	newSempTokenEphemeralResource,
+}
+
+// sempTokenEphemeralResource obtains a SEMP bearer token from an OAuth2 token endpoint with the client credentials
+// grant. The token is only available as an ephemeral value and never stored in the plan or state.
+type sempTokenEphemeralResource struct {
+	client *brokerClient
+}
+
+type sempTokenData struct {
+	TokenUrl     types.String `tfsdk:"token_url"`
+	ClientId     types.String `tfsdk:"client_id"`
+	ClientSecret types.String `tfsdk:"client_secret"`
+	Scopes       types.String `tfsdk:"scopes"`
+	BearerToken  types.String `tfsdk:"bearer_token"`
+	ExpiresAt    types.String `tfsdk:"expires_at"`
+}
+
+// sempTokenRequest is the private data of the ephemeral resource, with which Renew requests a new token
+type sempTokenRequest struct {
+	TokenUrl     string   `json:"token_url"`
+	ClientId     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	BearerToken  string   `json:"bearer_token"`
+}
+
+func newSempTokenEphemeralResource() ephemeral.EphemeralResource {
+	return &sempTokenEphemeralResource{}
+}
+
+func (r *sempTokenEphemeralResource) Metadata(_ context.Context, request ephemeral.MetadataRequest, response *ephemeral.MetadataResponse) {
+	response.TypeName = request.ProviderTypeName + "_semp_token"
+}
+
+func (r *sempTokenEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, response *ephemeral.SchemaResponse) {
+	response.Schema = schema.Schema{
+		MarkdownDescription: "Obtains a SEMP bearer token from an OAuth2 token endpoint with the client credentials grant, for example to configure the `bearer_token` of another `solacebroker` provider. The token is an ephemeral value that is never stored in the plan or state, and a new token is requested each time Terraform opens the ephemeral resource, and again shortly before it expires during long operations. The token endpoint is reached with the proxy, TLS and timeout settings of the provider, which needs no broker credentials for this. Requires Terraform 1.10 or later.",
+		Attributes: map[string]schema.Attribute{
+			"token_url": schema.StringAttribute{
+				MarkdownDescription: "The URL of the OAuth2 token endpoint.",
+				Required:            true,
+			},
+			"client_id": schema.StringAttribute{
+				MarkdownDescription: "The OAuth2 client ID to obtain the bearer token with.",
+				Required:            true,
+			},
+			"client_secret": schema.StringAttribute{
+				MarkdownDescription: "The OAuth2 client secret to obtain the bearer token with.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"scopes": schema.StringAttribute{
+				MarkdownDescription: "A space-separated list of scopes to request with the bearer token.",
+				Optional:            true,
+			},
+			"bearer_token": schema.StringAttribute{
+				MarkdownDescription: "The bearer token obtained from the token endpoint.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"expires_at": schema.StringAttribute{
+				MarkdownDescription: "The time the bearer token expires, in RFC 3339 format. Null if the token endpoint doesn't specify the lifetime of the token.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *sempTokenEphemeralResource) Configure(_ context.Context, request ephemeral.ConfigureRequest, response *ephemeral.ConfigureResponse) {
+	if request.ProviderData == nil {
+		return
+	}
+	client, ok := request.ProviderData.(*brokerClient)
+	if !ok {
+		response.Diagnostics.AddError(
+			"Unexpected ephemeral resource configuration",
+			fmt.Sprintf("Unexpected type %T for provider data; expected %T.", request.ProviderData, client),
+		)
+		return
+	}
+	r.client = client
+}
+
+func (r *sempTokenEphemeralResource) Open(ctx context.Context, request ephemeral.OpenRequest, response *ephemeral.OpenResponse) {
+	if r.client == nil {
+		// the provider configuration is not known yet
+		if request.ClientCapabilities.DeferralAllowed {
+			response.Deferred = &ephemeral.Deferred{Reason: ephemeral.DeferredReasonProviderConfigUnknown}
+			return
+		}
+		response.Diagnostics.AddError("Provider not configured", "The SEMP token cannot be obtained before the provider configuration is known.")
+		return
+	}
+	var data sempTokenData
+	response.Diagnostics.Append(request.Config.Get(ctx, &data)...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	tokenRequest := sempTokenRequest{
+		TokenUrl:     data.TokenUrl.ValueString(),
+		ClientId:     data.ClientId.ValueString(),
+		ClientSecret: data.ClientSecret.ValueString(),
+		Scopes:       strings.Fields(data.Scopes.ValueString()),
+	}
+	requested := time.Now()
+	expiresIn, err := r.requestToken(ctx, &tokenRequest)
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error obtaining SEMP token", err)
+		return
+	}
+	data.BearerToken = types.StringValue(tokenRequest.BearerToken)
+	data.ExpiresAt = types.StringNull()
+	if expiresIn > 0 {
+		data.ExpiresAt = types.StringValue(requested.Add(expiresIn).UTC().Format(time.RFC3339))
+	}
+	response.Diagnostics.Append(response.Result.Set(ctx, &data)...)
+	response.Diagnostics.Append(response.Private.SetKey(ctx, sempTokenPrivateKey, tokenRequest.privateData())...)
+	response.RenewAt = sempTokenRenewAt(requested, expiresIn)
+}
+
+// Renew requests a new token before the current one expires. The result of Open cannot change, the new token is
+// passed on in the private data.
+func (r *sempTokenEphemeralResource) Renew(ctx context.Context, request ephemeral.RenewRequest, response *ephemeral.RenewResponse) {
+	if r.client == nil {
+		response.Diagnostics.AddError("Provider not configured", "The SEMP token cannot be renewed before the provider configuration is known.")
+		return
+	}
+	privateData, diags := request.Private.GetKey(ctx, sempTokenPrivateKey)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	var tokenRequest sempTokenRequest
+	if err := json.Unmarshal(privateData, &tokenRequest); err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error reading SEMP token request", err)
+		return
+	}
+	requested := time.Now()
+	expiresIn, err := r.requestToken(ctx, &tokenRequest)
+	if err != nil {
+		addErrorToDiagnostics(&response.Diagnostics, "Error renewing SEMP token", err)
+		return
+	}
+	response.Diagnostics.Append(response.Private.SetKey(ctx, sempTokenPrivateKey, tokenRequest.privateData())...)
+	response.RenewAt = sempTokenRenewAt(requested, expiresIn)
+}
+
+// requestToken obtains a new token for the request, setting its bearer token, and returns the lifetime of the token
+func (r *sempTokenEphemeralResource) requestToken(ctx context.Context, tokenRequest *sempTokenRequest) (time.Duration, error) {
+	token, expiresIn, err := r.client.RequestOAuthToken(ctx, tokenRequest.TokenUrl, tokenRequest.ClientId, tokenRequest.ClientSecret, tokenRequest.Scopes)
+	if err != nil {
+		return 0, err
+	}
+	tokenRequest.BearerToken = token
+	return expiresIn, nil
+}
+
+// privateData returns the token request as JSON for the private data, it only contains strings and can't fail
+func (tokenRequest sempTokenRequest) privateData() []byte {
+	data, _ := json.Marshal(tokenRequest)
+	return data
+}
+
+// sempTokenRenewAt returns when to renew a token with the lifetime, zero if it doesn't expire
+func sempTokenRenewAt(requested time.Time, expiresIn time.Duration) time.Time {
+	if expiresIn <= 0 {
+		return time.Time{}
+	}
+	return requested.Add(expiresIn - min(sempTokenRenewMargin, expiresIn/2))
+}
@@ -0,0 +1,140 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+
+	"terraform-provider-solacebroker/internal/semp"
+)
+
+func TestSempTokenOpen(t *testing.T) {
+	issued := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, clientSecret, _ := r.BasicAuth()
+		if clientID != "client" || clientSecret != "secret" || r.FormValue("scope") != "semp:read semp:write" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		issued++
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":300}`, issued)
+	}))
+	t.Cleanup(tokenServer.Close)
+
+	ctx := context.Background()
+	r := newSempTokenEphemeralResource().(*sempTokenEphemeralResource)
+	schemaResponse := &ephemeral.SchemaResponse{}
+	r.Schema(ctx, ephemeral.SchemaRequest{}, schemaResponse)
+	configType := schemaResponse.Schema.Type().TerraformType(ctx)
+	open := func(clientSecret string, deferralAllowed bool) *ephemeral.OpenResponse {
+		request := ephemeral.OpenRequest{
+			Config: tfsdk.Config{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(configType, map[string]tftypes.Value{
+				"token_url":     tftypes.NewValue(tftypes.String, tokenServer.URL),
+				"client_id":     tftypes.NewValue(tftypes.String, "client"),
+				"client_secret": tftypes.NewValue(tftypes.String, clientSecret),
+				"scopes":        tftypes.NewValue(tftypes.String, "semp:read  semp:write"),
+				"bearer_token":  tftypes.NewValue(tftypes.String, nil),
+				"expires_at":    tftypes.NewValue(tftypes.String, nil),
+			})},
+			ClientCapabilities: ephemeral.OpenClientCapabilities{DeferralAllowed: deferralAllowed},
+		}
+		response := &ephemeral.OpenResponse{Result: tfsdk.EphemeralResultData{Schema: schemaResponse.Schema}}
+		// the framework provides empty private data, its type is internal
+		reflect.ValueOf(&response.Private).Elem().Set(reflect.New(reflect.TypeOf(response.Private).Elem()))
+		r.Open(ctx, request, response)
+		return response
+	}
+
+	if response := open("secret", true); response.Deferred == nil || response.Diagnostics.HasError() {
+		t.Errorf("Open() of an unconfigured provider = %v, %v, want it deferred", response.Deferred, response.Diagnostics)
+	}
+	if response := open("secret", false); !response.Diagnostics.HasError() {
+		t.Errorf("Open() of an unconfigured provider succeeded, want an error if deferral is not allowed")
+	}
+
+	r.Configure(ctx, ephemeral.ConfigureRequest{ProviderData: &brokerClient{Client: semp.NewClient("", false, false, semp.Retries(0, 0, 0))}}, &ephemeral.ConfigureResponse{})
+	for i := 1; i <= 2; i++ {
+		before := time.Now()
+		response := open("secret", false)
+		if response.Diagnostics.HasError() {
+			t.Fatalf("Open() diagnostics: %v", response.Diagnostics)
+		}
+		var data sempTokenData
+		response.Diagnostics.Append(response.Result.Get(ctx, &data)...)
+		if response.Diagnostics.HasError() {
+			t.Fatalf("Open() result: %v", response.Diagnostics)
+		}
+		if want := fmt.Sprintf("token-%d", i); data.BearerToken.ValueString() != want {
+			t.Errorf("Open() bearer token = %v, want a new token %v", data.BearerToken.ValueString(), want)
+		}
+		expiresAt, err := time.Parse(time.RFC3339, data.ExpiresAt.ValueString())
+		if err != nil || expiresAt.Before(before.Add(299*time.Second)) || expiresAt.After(time.Now().Add(300*time.Second)) {
+			t.Errorf("Open() expires at %v (%v), want in 5 minutes", data.ExpiresAt.ValueString(), err)
+		}
+		if response.RenewAt.Before(before.Add(4*time.Minute)) || response.RenewAt.After(time.Now().Add(4*time.Minute)) {
+			t.Errorf("Open() renew at %v, want a minute before the token expires", response.RenewAt)
+		}
+	}
+
+	// Renew obtains a new token with the request in the private data
+	openResponse := open("secret", false)
+	renewResponse := &ephemeral.RenewResponse{Private: openResponse.Private}
+	before := time.Now()
+	r.Renew(ctx, ephemeral.RenewRequest{Private: openResponse.Private}, renewResponse)
+	if renewResponse.Diagnostics.HasError() {
+		t.Fatalf("Renew() diagnostics: %v", renewResponse.Diagnostics)
+	}
+	privateData, _ := renewResponse.Private.GetKey(ctx, sempTokenPrivateKey)
+	var tokenRequest sempTokenRequest
+	if err := json.Unmarshal(privateData, &tokenRequest); err != nil || tokenRequest.BearerToken != "token-4" {
+		t.Errorf("Renew() private data = %s (%v), want the new token token-4", privateData, err)
+	}
+	if renewResponse.RenewAt.Before(before.Add(4*time.Minute)) || renewResponse.RenewAt.After(time.Now().Add(4*time.Minute)) {
+		t.Errorf("Renew() renew at %v, want a minute before the new token expires", renewResponse.RenewAt)
+	}
+	if response := open("wrong", false); !response.Diagnostics.HasError() {
+		t.Errorf("Open() with a wrong client secret succeeded, want an error")
+	}
+}
+
+func TestSempTokenRenewAt(t *testing.T) {
+	requested := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		expiresIn time.Duration
+		want      time.Time
+	}{
+		{0, time.Time{}},
+		{time.Hour, requested.Add(59 * time.Minute)},
+		{time.Minute, requested.Add(30 * time.Second)},
+	}
+	for _, tt := range tests {
+		if got := sempTokenRenewAt(requested, tt.expiresIn); !got.Equal(tt.want) {
+			t.Errorf("sempTokenRenewAt(%v) = %v, want %v", tt.expiresIn, got, tt.want)
+		}
+	}
+}
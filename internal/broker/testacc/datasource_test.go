@@ -46,6 +46,41 @@ data "solacebroker_msg_vpn" "default" {
 	})
 }
 
+func TestAccListDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: ProviderConfig + `
+resource "solacebroker_msg_vpn_queue" "orders" {
+		msg_vpn_name = "default"
+		queue_name = "orders.test"
+}
+
+resource "solacebroker_msg_vpn_queue" "other" {
+		msg_vpn_name = "default"
+		queue_name = "other.test"
+}
+
+data "solacebroker_msg_vpn_queues" "orders" {
+		msg_vpn_name = "default"
+		where = ["queue_name==orders.*"]
+		select = ["max_bind_count"]
+		depends_on = [solacebroker_msg_vpn_queue.orders, solacebroker_msg_vpn_queue.other]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.solacebroker_msg_vpn_queues.orders", "queues.#", "1"),
+					resource.TestCheckResourceAttr("data.solacebroker_msg_vpn_queues.orders", "queues.0.queue_name", "orders.test"),
+					resource.TestCheckResourceAttr("data.solacebroker_msg_vpn_queues.orders", "queues.0.max_bind_count", "1000"),
+				),
+			},
+		},
+	})
+}
+
 func TestAllDataSourceSchemas(t *testing.T) {
 	t.Parallel()
 
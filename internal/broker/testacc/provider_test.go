@@ -18,6 +18,7 @@ package acctest
 
 import (
 	"os"
+	"strings"
 	"terraform-provider-solacebroker/internal/broker"
 	"testing"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/testcontainers/testcontainers-go/wait"
 
 	"terraform-provider-solacebroker/internal/broker/generated"
+	"terraform-provider-solacebroker/internal/broker/sempmock"
 )
 
 var ProviderConfig string
@@ -46,7 +48,49 @@ var (
 	}
 )
 
-func init() {
+// TestMain starts the broker for the acceptance tests: a solace-pubsub-standard container, or an in-process mock
+// broker if TESTACC_MOCK_BROKER is set. Nothing is started if acceptance tests are not enabled with TF_ACC.
+func TestMain(m *testing.M) {
+	if os.Getenv("TF_ACC") == "" {
+		os.Exit(m.Run())
+	}
+	var endpoint string
+	if os.Getenv("TESTACC_MOCK_BROKER") != "" {
+		endpoint = startMockBroker()
+	} else {
+		endpoint = startBrokerContainer()
+	}
+	ProviderConfig = `
+provider "solacebroker" {
+username = "admin"
+password = "admin"
+url      = "http://` + endpoint + `"
+}
+`
+	const user = "admin"
+	const password = "admin"
+
+	if err := os.Setenv("SOLACEBROKER_URL", "http://"+endpoint); err != nil {
+		panic(err)
+	}
+
+	if err := os.Setenv("SOLACEBROKER_USERNAME", user); err != nil {
+		panic(err)
+	}
+
+	if err := os.Setenv("SOLACEBROKER_PASSWORD", password); err != nil {
+		panic(err)
+	}
+
+	if generated.Platform == "Appliance" {
+		if err := os.Setenv("SOLACEBROKER_SKIP_API_CHECK", "true"); err != nil {
+			panic(err)
+		}
+	}
+	os.Exit(m.Run())
+}
+
+func startBrokerContainer() string {
 	// start docker test broker
 	ctx := context.Background()
 	req := testcontainers.ContainerRequest{
@@ -88,33 +132,23 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
-	ProviderConfig = `
-provider "solacebroker" {
-username = "admin"
-password = "admin"
-url      = "http://` + endpoint + `"
+	return endpoint
 }
-`
-	const user = "admin"
-	const password = "admin"
-
-	if err = os.Setenv("SOLACEBROKER_URL", "http://"+endpoint); err != nil {
-		panic(err)
-	}
 
-	if err = os.Setenv("SOLACEBROKER_USERNAME", user); err != nil {
-		panic(err)
-	}
-
-	if err = os.Setenv("SOLACEBROKER_PASSWORD", password); err != nil {
-		panic(err)
-	}
-
-	if generated.Platform == "Appliance" {
-		if err = os.Setenv("SOLACEBROKER_SKIP_API_CHECK", "true"); err != nil {
+func startMockBroker() string {
+	mockBroker := sempmock.New(sempmock.BasicAuth("admin", "admin"))
+	// the default objects that exist on a new broker
+	for _, path := range []string{
+		"/msgVpns/default",
+		"/msgVpns/default/aclProfiles/default",
+		"/msgVpns/default/clientProfiles/default",
+		"/msgVpns/default/clientUsernames/default",
+	} {
+		if err := mockBroker.SetObject(path, map[string]any{}); err != nil {
 			panic(err)
 		}
 	}
+	return strings.TrimPrefix(mockBroker.URL, "http://")
 }
 
 func testAccPreCheck(t *testing.T) {
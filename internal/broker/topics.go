@@ -0,0 +1,111 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxTopicLength           = 250
+	topicLevelSeparator      = "/"
+	sharedSubscriptionPrefix = "#share/"
+	noExportPrefix           = "#noexport/"
+)
+
+// topicSubscription is a topic subscription split into its optional #noexport/ and #share/<share name>/ prefixes and
+// the topic it subscribes to
+type topicSubscription struct {
+	noExport  bool
+	shareName string
+	topic     string
+}
+
+// validateTopic returns an error if topic is not a valid SMF topic or topic subscription: between 1 and 250 bytes of
+// UTF-8 without null characters, in levels that must not be empty
+func validateTopic(topic string) error {
+	if topic == "" {
+		return fmt.Errorf("topic must not be empty")
+	}
+	if len(topic) > maxTopicLength {
+		return fmt.Errorf("topic %q is longer than %d bytes", topic, maxTopicLength)
+	}
+	if !utf8.ValidString(topic) || strings.ContainsRune(topic, 0) {
+		return fmt.Errorf("topic %q must be UTF-8 without null characters", topic)
+	}
+	for _, level := range strings.Split(topic, topicLevelSeparator) {
+		if level == "" {
+			return fmt.Errorf("topic %q has an empty level", topic)
+		}
+	}
+	return nil
+}
+
+// isSharedSubscription returns true if the subscription starts with #share/, optionally preceded by #noexport/
+func isSharedSubscription(subscription string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(subscription, noExportPrefix), sharedSubscriptionPrefix)
+}
+
+// parseSubscription validates a topic subscription and splits off its #noexport/ and #share/<share name>/ prefixes
+func parseSubscription(subscription string) (topicSubscription, error) {
+	if err := validateTopic(subscription); err != nil {
+		return topicSubscription{}, err
+	}
+	result := topicSubscription{topic: subscription}
+	if strings.HasPrefix(result.topic, noExportPrefix) {
+		result.noExport = true
+		result.topic = strings.TrimPrefix(result.topic, noExportPrefix)
+	}
+	if strings.HasPrefix(result.topic, sharedSubscriptionPrefix) {
+		shareName, topic, found := strings.Cut(strings.TrimPrefix(result.topic, sharedSubscriptionPrefix), topicLevelSeparator)
+		if !found {
+			return topicSubscription{}, fmt.Errorf("shared subscription %q must have the form #share/<share name>/<topic>", subscription)
+		}
+		if strings.ContainsAny(shareName, "*>") {
+			return topicSubscription{}, fmt.Errorf("share name %q of subscription %q must not contain wildcards", shareName, subscription)
+		}
+		result.shareName = shareName
+		result.topic = topic
+	}
+	return result, nil
+}
+
+// topicMatches returns true if a topic subscription, without #noexport/ and #share/ prefixes, matches a topic. A level
+// "*" matches any single level, a level ending in "*" any level starting with the rest of it and a last level ">" one
+// or more levels. Elsewhere "*" and ">" are literal characters.
+func topicMatches(subscription string, topic string) bool {
+	subscriptionLevels := strings.Split(subscription, topicLevelSeparator)
+	topicLevels := strings.Split(topic, topicLevelSeparator)
+	for i, level := range subscriptionLevels {
+		if level == ">" && i == len(subscriptionLevels)-1 {
+			return len(topicLevels) > i
+		}
+		if i >= len(topicLevels) {
+			return false
+		}
+		if strings.HasSuffix(level, "*") {
+			if !strings.HasPrefix(topicLevels[i], strings.TrimSuffix(level, "*")) {
+				return false
+			}
+		} else if level != topicLevels[i] {
+			return false
+		}
+	}
+	return len(subscriptionLevels) == len(topicLevels)
+}
@@ -0,0 +1,101 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package broker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		subscription string
+		topic        string
+		want         bool
+	}{
+		{"a/b/c", "a/b/c", true},
+		{"a/b/c", "a/b", false},
+		{"a/b", "a/b/c", false},
+		{"a/*/c", "a/b/c", true},
+		{"a/*/c", "a/b/x/c", false},
+		{"a/*", "a", false},
+		{"a/b*/c", "a/bcd/c", true},
+		{"a/b*/c", "a/b/c", true},
+		{"a/b*/c", "a/xb/c", false},
+		{"a/b*c/d", "a/bxc/d", false},
+		{"a/b*c/d", "a/b*c/d", true},
+		{"a/>", "a/b", true},
+		{"a/>", "a/b/c/d", true},
+		{"a/>", "a", false},
+		{"a/*/c/>", "a/b/c/d", true},
+		{"a/*/c/>", "a/b/c", false},
+		{">", "a/b", true},
+		{"a/>/c", "a/b/c", false},
+		{"a/>/c", "a/>/c", true},
+		{"a/b>", "a/b>", true},
+		{"a/b>", "a/bc", false},
+	}
+	for _, tt := range tests {
+		if got := topicMatches(tt.subscription, tt.topic); got != tt.want {
+			t.Errorf("topicMatches(%v, %v) = %v, want %v", tt.subscription, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTopic(t *testing.T) {
+	valid := []string{"a", "a/b/c", "a/*/c/>", "#P2P/v:router/client", strings.Repeat("a", 250)}
+	for _, topic := range valid {
+		if err := validateTopic(topic); err != nil {
+			t.Errorf("validateTopic(%v) = %v, want no error", topic, err)
+		}
+	}
+	invalid := []string{"", "/a", "a/", "a//b", strings.Repeat("a", 251), "a\x00b", "a\xffb"}
+	for _, topic := range invalid {
+		if err := validateTopic(topic); err == nil {
+			t.Errorf("validateTopic(%q) succeeded, want an error", topic)
+		}
+	}
+}
+
+func TestParseSubscription(t *testing.T) {
+	tests := []struct {
+		subscription string
+		want         topicSubscription
+		wantErr      bool
+	}{
+		{"a/>", topicSubscription{topic: "a/>"}, false},
+		{"#share/group/a/*", topicSubscription{shareName: "group", topic: "a/*"}, false},
+		{"#noexport/a/b", topicSubscription{noExport: true, topic: "a/b"}, false},
+		{"#noexport/#share/group/a", topicSubscription{noExport: true, shareName: "group", topic: "a"}, false},
+		{"#share/#noexport/a", topicSubscription{shareName: "#noexport", topic: "a"}, false},
+		{"#share/group", topicSubscription{}, true},
+		{"#share//a", topicSubscription{}, true},
+		{"#share/gr*/a", topicSubscription{}, true},
+		{"#share/>/a", topicSubscription{}, true},
+		{"#noexport/", topicSubscription{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSubscription(tt.subscription)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSubscription(%v) = %+v, %v, want %+v", tt.subscription, got, err, tt.want)
+		}
+	}
+	for subscription, want := range map[string]bool{"#share/g/a": true, "#noexport/#share/g/a": true, "a/#share/g": false, "#share": false} {
+		if got := isSharedSubscription(subscription); got != want {
+			t.Errorf("isSharedSubscription(%v) = %v, want %v", subscription, got, want)
+		}
+	}
+}
@@ -17,6 +17,8 @@
 package broker
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/url"
 	"os"
@@ -134,13 +136,25 @@ func durationWithDefaultFromEnv(value types.String, name string, def time.Durati
 	return d, nil
 }
 
-func client(providerData *providerData) (*semp.Client, diag.Diagnostic) {
+// noCredentialsSummary is the summary of the diagnostic of client if the provider has no credentials at all
+const noCredentialsSummary = "Bearer token, basic authentication credentials, OAuth client credentials or client certificate must be provided"
+
+func client(providerData *providerData) (*brokerClient, diag.Diagnostic) {
 	// Check for params credentials conflicts
 	// Logic:
 	// If there is any 1 complete set of credentials in the provider block those are always used and are the priority.
 	// If there is not any 1 complete set of credentials in the provider block then look for 1 complete set in the env vars.
 	// If there are multiple complete sets in either the provider block or env vars this is an error.
 	// If there are no complete sets in the env vars this is an error.
+	// A client certificate can be used on its own or together with any one set of credentials
+	clientCertificate, err := clientCertificateWithDefaultFromEnv(providerData)
+	if err != nil {
+		return nil, diag.NewErrorDiagnostic("Unable to load client certificate", err.Error())
+	}
+	oauthTokenURL, oauthClientID, oauthClientSecret, oauthScopes, err := oauthClientCredentialsWithDefaultFromEnv(providerData)
+	if err != nil {
+		return nil, diag.NewErrorDiagnostic("Invalid OAuth client credentials", err.Error())
+	}
 	var username, password, bearerToken string
 	if !providerData.BearerToken.IsNull() && providerData.Username.IsNull() && providerData.Password.IsNull() ||
 		providerData.BearerToken.IsNull() && !providerData.Username.IsNull() && !providerData.Password.IsNull() {
@@ -163,8 +177,8 @@ func client(providerData *providerData) (*semp.Client, diag.Diagnostic) {
 		if err != nil {
 			return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 		}
-		if username == "" && password == "" && bearerToken == "" {
-			return nil, diag.NewErrorDiagnostic("Bearer token or basic authentication credentials must be provided", semp.ErrProviderParametersError.Error())
+		if username == "" && password == "" && bearerToken == "" && oauthTokenURL == "" && clientCertificate == nil {
+			return nil, diag.NewErrorDiagnostic(noCredentialsSummary, semp.ErrProviderParametersError.Error())
 		}
 		if (!providerData.BearerToken.IsNull() && (!providerData.Username.IsNull() || !providerData.Password.IsNull())) ||
 			(bearerToken != "" && (username != "" || password != "")) {
@@ -175,48 +189,173 @@ func client(providerData *providerData) (*semp.Client, diag.Diagnostic) {
 			return nil, diag.NewErrorDiagnostic("Both username and password must be provided for basic authentication and cannot mix params and env vars", semp.ErrProviderParametersError.Error())
 		}
 	}
-	url, err := stringWithDefaultFromEnv(providerData.Url, "url")
+	if oauthTokenURL != "" && (bearerToken != "" || username != "" || password != "") {
+		return nil, diag.NewErrorDiagnostic("Cannot use OAuth client credentials with Bearer token or basic authentication credentials", semp.ErrProviderParametersError.Error())
+	}
+	url, insecureSkipVerify, options, d := connectionOptions(providerData)
+	if d != nil {
+		return nil, d
+	}
+	skipApiCheck, err := booleanWithDefaultFromEnv(providerData.SkipApiCheck, "skip_api_check", false)
 	if err != nil {
 		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
-	retries, err := int64WithDefaultFromEnv(providerData.Retries, "retries", semp.DefaultRetries)
+	updateMode, err := stringWithDefaultFromEnv(providerData.UpdateMode, "update_mode")
 	if err != nil {
 		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
+	switch updateMode {
+	case "":
+		updateMode = updateModePut
+	case updateModePut, updateModePatch:
+	default:
+		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", fmt.Sprintf("invalid update_mode %q, must be %q or %q", updateMode, updateModePut, updateModePatch))
+	}
+	client := semp.NewClient(
+		url,
+		insecureSkipVerify,
+		true, // this is a client for the provider
+		append(options,
+			semp.BasicAuth(username, password),
+			semp.BearerToken(bearerToken),
+			semp.OAuthClientCredentials(oauthTokenURL, oauthClientID, oauthClientSecret, oauthScopes),
+			semp.ClientCertificate(clientCertificate))...)
+	return &brokerClient{
+		Client:       client,
+		skipApiCheck: skipApiCheck,
+		updateMode:   updateMode,
+	}, nil
+}
+
+// tokenClient returns a client with the connection settings of the provider but without broker credentials, which
+// can obtain SEMP tokens even if the provider has no credentials
+func tokenClient(providerData *providerData) (*brokerClient, diag.Diagnostic) {
+	url, insecureSkipVerify, options, d := connectionOptions(providerData)
+	if d != nil {
+		return nil, d
+	}
+	return &brokerClient{Client: semp.NewClient(url, insecureSkipVerify, true, options...)}, nil
+}
+
+// connectionOptions returns the full SEMP URL, whether to skip the verification of server certificates and the client
+// options that don't depend on the credentials
+func connectionOptions(providerData *providerData) (string, bool, []semp.Option, diag.Diagnostic) {
+	url, err := stringWithDefaultFromEnv(providerData.Url, "url")
+	if err != nil {
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+	}
+	retries, err := int64WithDefaultFromEnv(providerData.Retries, "retries", semp.DefaultRetries)
+	if err != nil {
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+	}
 	retryMinInterval, err := durationWithDefaultFromEnv(providerData.RetryMinInterval, "retry_min_interval", semp.DefaultRetryMinInterval)
 	if err != nil {
-		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
 	retryMaxInterval, err := durationWithDefaultFromEnv(providerData.RetryMaxInterval, "retry_max_interval", semp.DefaultRetryMaxInterval)
 	if err != nil {
-		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
 	requestTimeoutDuration, err := durationWithDefaultFromEnv(providerData.RequestTimeoutDuration, "request_timeout_duration", semp.DefaultRequestTimeout)
 	if err != nil {
-		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
 	requestMinInterval, err := durationWithDefaultFromEnv(providerData.RequestMinInterval, "request_min_interval", semp.DefaultRequestInterval)
 	if err != nil {
-		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
 	insecureSkipVerify, err := booleanWithDefaultFromEnv(providerData.InsecureSkipVerify, "insecure_skip_verify", false)
 	if err != nil {
-		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
 	}
-	url = getFullSempAPIURL(url)
-	skipApiCheck, err = booleanWithDefaultFromEnv(providerData.SkipApiCheck, "skip_api_check", false) // This variable is used in resource
+	rootCAs, err := rootCAsWithDefaultFromEnv(providerData)
 	if err != nil {
-		return nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to load CA certificates", err.Error())
 	}
-	client := semp.NewClient(
-		url,
-		insecureSkipVerify,
-		true, // this is a client for the provider
-		semp.BasicAuth(username, password),
-		semp.BearerToken(bearerToken),
+	tlsServerName, err := stringWithDefaultFromEnv(providerData.TlsServerName, "tls_server_name")
+	if err != nil {
+		return "", false, nil, diag.NewErrorDiagnostic("Unable to parse provider attribute", err.Error())
+	}
+	options := []semp.Option{
+		semp.RootCAs(rootCAs),
+		semp.TLSServerName(tlsServerName),
 		semp.Retries(retries, retryMinInterval, retryMaxInterval),
-		semp.RequestLimits(requestTimeoutDuration, requestMinInterval))
-	return client, nil
+		semp.RequestLimits(requestTimeoutDuration, requestMinInterval),
+	}
+	return getFullSempAPIURL(url), insecureSkipVerify, options, nil
+}
+
+func oauthClientCredentialsWithDefaultFromEnv(providerData *providerData) (string, string, string, []string, error) {
+	tokenURL, err := stringWithDefaultFromEnv(providerData.OauthTokenUrl, "oauth_token_url")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	clientID, err := stringWithDefaultFromEnv(providerData.OauthClientId, "oauth_client_id")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	clientSecret, err := stringWithDefaultFromEnv(providerData.OauthClientSecret, "oauth_client_secret")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	scopes, err := stringWithDefaultFromEnv(providerData.OauthScopes, "oauth_scopes")
+	if err != nil {
+		return "", "", "", nil, err
+	}
+	if tokenURL == "" {
+		if clientID != "" || clientSecret != "" || scopes != "" {
+			return "", "", "", nil, fmt.Errorf("oauth_token_url must be provided for OAuth client credentials")
+		}
+		return "", "", "", nil, nil
+	}
+	if clientID == "" || clientSecret == "" {
+		return "", "", "", nil, fmt.Errorf("both oauth_client_id and oauth_client_secret must be provided with oauth_token_url")
+	}
+	return tokenURL, clientID, clientSecret, strings.Fields(scopes), nil
+}
+
+func clientCertificateWithDefaultFromEnv(providerData *providerData) (*tls.Certificate, error) {
+	certificate, err := stringWithDefaultFromEnv(providerData.ClientCertificate, "client_certificate")
+	if err != nil {
+		return nil, err
+	}
+	certificateFile, err := stringWithDefaultFromEnv(providerData.ClientCertificateFile, "client_certificate_file")
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := stringWithDefaultFromEnv(providerData.ClientPrivateKey, "client_private_key")
+	if err != nil {
+		return nil, err
+	}
+	privateKeyFile, err := stringWithDefaultFromEnv(providerData.ClientPrivateKeyFile, "client_private_key_file")
+	if err != nil {
+		return nil, err
+	}
+	certificatePEM, err := semp.ReadPEM("client_certificate", certificate, "client_certificate_file", certificateFile)
+	if err != nil {
+		return nil, err
+	}
+	privateKeyPEM, err := semp.ReadPEM("client_private_key", privateKey, "client_private_key_file", privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	return semp.LoadClientCertificate(certificatePEM, privateKeyPEM)
+}
+
+func rootCAsWithDefaultFromEnv(providerData *providerData) (*x509.CertPool, error) {
+	caCertificate, err := stringWithDefaultFromEnv(providerData.CaCertificate, "ca_certificate")
+	if err != nil {
+		return nil, err
+	}
+	caBundleFile, err := stringWithDefaultFromEnv(providerData.CaBundleFile, "ca_bundle_file")
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := semp.ReadPEM("ca_certificate", caCertificate, "ca_bundle_file", caBundleFile)
+	if err != nil {
+		return nil, err
+	}
+	return semp.LoadCertPool(caPEM)
 }
 
 func getFullSempAPIURL(url string) string {
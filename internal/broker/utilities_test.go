@@ -1,10 +1,26 @@
 package broker
 
 import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 )
 
 func TestClient(t *testing.T) {
@@ -23,7 +39,7 @@ func TestClient(t *testing.T) {
 		{"", "", "", "testuser", "testpassword", "testbearertoken", "Cannot use Bearer token with basic authentication credentials"},
 		{"", "", "", "testuser", "", "testbearertoken", "Cannot use Bearer token with basic authentication credentials"},
 		{"", "", "", "", "testpassword", "testbearertoken", "Cannot use Bearer token with basic authentication credentials"},
-		{"", "", "", "", "", "", "Bearer token or basic authentication credentials must be provided"},
+		{"", "", "", "", "", "", "Bearer token, basic authentication credentials, OAuth client credentials or client certificate must be provided"},
 		{"testuser", "testpassword", "", "", "", "", ""},
 		{"", "", "testbearertoken", "", "", "", ""},
 		{"", "", "testbearertoken", "", "", "testbearertoken", ""},
@@ -83,3 +99,283 @@ func TestClient(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateMode(t *testing.T) {
+	os.Setenv("SOLACEBROKER_USERNAME", "")
+	os.Setenv("SOLACEBROKER_PASSWORD", "")
+	os.Setenv("SOLACEBROKER_BEARER_TOKEN", "")
+	matrix := []struct {
+		ParamUpdateMode string
+		EnvUpdateMode   string
+		Expected        string
+		ExpectedError   bool
+	}{
+		{"", "", "put", false},
+		{"patch", "", "patch", false},
+		{"", "patch", "patch", false},
+		{"put", "patch", "put", false},
+		{"merge", "", "", true},
+	}
+	for testNr, test := range matrix {
+		os.Setenv("SOLACEBROKER_UPDATE_MODE", test.EnvUpdateMode)
+		updateMode := types.StringNull()
+		if test.ParamUpdateMode != "" {
+			updateMode = types.StringValue(test.ParamUpdateMode)
+		}
+		providerData := &providerData{
+			Username:   types.StringValue("testuser"),
+			Password:   types.StringValue("testpassword"),
+			Url:        types.StringValue("https://example.com"),
+			UpdateMode: updateMode,
+		}
+		brokerClient, diag := client(providerData)
+		if test.ExpectedError {
+			if diag == nil {
+				t.Errorf("Test %d: expected error but got nil diag", testNr)
+			}
+			continue
+		}
+		if diag != nil {
+			t.Errorf("Test %d: unexpected error %v", testNr, diag)
+			continue
+		}
+		if brokerClient.updateMode != test.Expected {
+			t.Errorf("Test %d: expected update mode %v but got %v", testNr, test.Expected, brokerClient.updateMode)
+		}
+	}
+	os.Unsetenv("SOLACEBROKER_UPDATE_MODE")
+}
+
+func generateTestCertificate(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		DNSNames:              []string{"localhost"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func TestClientCertificate(t *testing.T) {
+	certPEM, keyPEM := generateTestCertificate(t)
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("SOLACEBROKER_USERNAME", "")
+	os.Setenv("SOLACEBROKER_PASSWORD", "")
+	os.Setenv("SOLACEBROKER_BEARER_TOKEN", "")
+
+	optional := func(s string) types.String {
+		if s == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(s)
+	}
+	matrix := []struct {
+		Certificate     string
+		CertificateFile string
+		PrivateKey      string
+		PrivateKeyFile  string
+		Expected        string
+	}{
+		{string(certPEM), "", string(keyPEM), "", ""},
+		{"", certFile, "", keyFile, ""},
+		{string(certPEM), "", "", keyFile, ""},
+		{string(certPEM), certFile, string(keyPEM), "", "Unable to load client certificate"},
+		{string(certPEM), "", "", "", "Unable to load client certificate"},
+		{"", certFile, "", certFile, "Unable to load client certificate"},
+		{"", filepath.Join(dir, "missing.pem"), "", keyFile, "Unable to load client certificate"},
+	}
+	for testNr, test := range matrix {
+		providerData := &providerData{
+			Username:              types.StringNull(),
+			Password:              types.StringNull(),
+			BearerToken:           types.StringNull(),
+			ClientCertificate:     optional(test.Certificate),
+			ClientCertificateFile: optional(test.CertificateFile),
+			ClientPrivateKey:      optional(test.PrivateKey),
+			ClientPrivateKeyFile:  optional(test.PrivateKeyFile),
+			Url:                   types.StringValue("https://example.com"),
+		}
+		_, diag := client(providerData)
+		if diag != nil {
+			if test.Expected != diag.Summary() {
+				t.Errorf("Test %d: expected %v but got %v: %v", testNr, test.Expected, diag.Summary(), diag.Detail())
+			}
+		} else if test.Expected != "" {
+			t.Errorf("Test %d: expected %v but got nil diag", testNr, test.Expected)
+		}
+	}
+}
+
+func TestRootCAs(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"platform":"VMR","sempVersion":"2.46"}}`))
+	}))
+	defer server.Close()
+	serverCA := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	otherCA, _ := generateTestCertificate(t)
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, serverCA, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	optional := func(s string) types.String {
+		if s == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(s)
+	}
+	matrix := []struct {
+		CaCertificate   string
+		CaBundleFile    string
+		TlsServerName   string
+		ExpectedDiag    string
+		ExpectedConnect bool
+	}{
+		{string(serverCA), "", "", "", true},
+		{"", caFile, "", "", true},
+		{"", caFile, "example.com", "", true},
+		{"", caFile, "solace.example.org", "", false},
+		{string(otherCA), "", "", "", false},
+		{"", "", "", "", false},
+		{string(serverCA), caFile, "", "Unable to load CA certificates", false},
+		{"not a certificate", "", "", "Unable to load CA certificates", false},
+	}
+	for testNr, test := range matrix {
+		providerData := &providerData{
+			Username:      types.StringValue("testuser"),
+			Password:      types.StringValue("testpassword"),
+			BearerToken:   types.StringNull(),
+			CaCertificate: optional(test.CaCertificate),
+			CaBundleFile:  optional(test.CaBundleFile),
+			TlsServerName: optional(test.TlsServerName),
+			Retries:       types.Int64Value(0),
+			Url:           types.StringValue(server.URL),
+		}
+		client, diag := client(providerData)
+		if diag != nil {
+			if test.ExpectedDiag != diag.Summary() {
+				t.Errorf("Test %d: expected %v but got %v: %v", testNr, test.ExpectedDiag, diag.Summary(), diag.Detail())
+			}
+			continue
+		} else if test.ExpectedDiag != "" {
+			t.Errorf("Test %d: expected %v but got nil diag", testNr, test.ExpectedDiag)
+			continue
+		}
+		_, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "about/api")
+		if test.ExpectedConnect && err != nil {
+			t.Errorf("Test %d: expected successful request but got %v", testNr, err)
+		} else if !test.ExpectedConnect && err == nil {
+			t.Errorf("Test %d: expected certificate validation failure but request succeeded", testNr)
+		}
+	}
+}
+
+func TestBrokerRequirementsCheckPerClient(t *testing.T) {
+	savedSempDetail := SempDetail
+	defer func() { SempDetail = savedSempDetail }()
+	RegisterSempVersionDetails("/SEMP/v2/config", "2.46", "VMR")
+
+	newBroker := func(platform string, aboutApiRequests *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*aboutApiRequests++
+			_, _ = w.Write([]byte(`{"data":{"platform":"` + platform + `","sempVersion":"2.46"}}`))
+		}))
+	}
+	var primaryRequests, applianceRequests int
+	primary := newBroker("VMR", &primaryRequests)
+	defer primary.Close()
+	appliance := newBroker("Appliance", &applianceRequests)
+	defer appliance.Close()
+
+	newClient := func(url string, skipApiCheck bool) *brokerClient {
+		c, diag := client(&providerData{
+			Username:     types.StringValue("testuser"),
+			Password:     types.StringValue("testpassword"),
+			BearerToken:  types.StringNull(),
+			Url:          types.StringValue(url),
+			SkipApiCheck: types.BoolValue(skipApiCheck),
+		})
+		if diag != nil {
+			t.Fatalf("unexpected diag %v: %v", diag.Summary(), diag.Detail())
+		}
+		return c
+	}
+	primaryClient := newClient(primary.URL, false)
+	applianceClient := newClient(appliance.URL, false)
+	skippingApplianceClient := newClient(appliance.URL, true)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := primaryClient.checkBrokerRequirements(ctx); err != nil {
+			t.Errorf("expected primary broker check to pass, got %v", err)
+		}
+		if err := applianceClient.checkBrokerRequirements(ctx); err == nil {
+			t.Error("expected appliance broker check to fail")
+		}
+		if err := skippingApplianceClient.checkBrokerRequirements(ctx); err != nil {
+			t.Errorf("expected skipped broker check to pass, got %v", err)
+		}
+	}
+	if primaryRequests != 1 {
+		t.Errorf("expected the primary broker API to be checked once, got %d", primaryRequests)
+	}
+	if applianceRequests != 2 {
+		t.Errorf("expected the failed appliance broker API check to be repeated, got %d checks", applianceRequests)
+	}
+}
+
+func TestConfigureWithoutCredentials(t *testing.T) {
+	t.Setenv("SOLACEBROKER_USERNAME", "")
+	t.Setenv("SOLACEBROKER_PASSWORD", "")
+	t.Setenv("SOLACEBROKER_BEARER_TOKEN", "")
+	ctx := context.Background()
+	p := New("test")()
+	schemaResponse := &provider.SchemaResponse{}
+	p.Schema(ctx, provider.SchemaRequest{}, schemaResponse)
+	configType := schemaResponse.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["url"] = tftypes.NewValue(tftypes.String, "https://example.com")
+	response := &provider.ConfigureResponse{}
+	p.Configure(ctx, provider.ConfigureRequest{Config: tfsdk.Config{Schema: schemaResponse.Schema, Raw: tftypes.NewValue(configType, values)}}, response)
+	if response.Diagnostics.HasError() {
+		t.Fatalf("Configure() without credentials diagnostics: %v", response.Diagnostics)
+	}
+	if client, ok := response.EphemeralResourceData.(*brokerClient); !ok || client.credentialsError != nil {
+		t.Errorf("Configure() ephemeral resource data = %v, want a client for obtaining tokens", response.EphemeralResourceData)
+	}
+	client, ok := response.ResourceData.(*brokerClient)
+	if !ok {
+		t.Fatalf("Configure() resource data = %v, want a client", response.ResourceData)
+	}
+	if err := client.checkBrokerRequirements(ctx); err == nil || !strings.Contains(err.Error(), noCredentialsSummary) {
+		t.Errorf("checkBrokerRequirements() = %v, want the missing credentials", err)
+	}
+}
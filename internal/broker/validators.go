@@ -0,0 +1,148 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/boolvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/helpers/validatordiag"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ resource.ConfigValidator   = &attributeRelationshipValidator{}
+	_ datasource.ConfigValidator = &attributeRelationshipValidator{}
+)
+
+// attributeRelationshipValidator enforces the Requires and ConflictsWith lists of the entity attributes, including
+// the attributes of nested Struct attributes, so that invalid combinations are reported at plan time.
+type attributeRelationshipValidator struct {
+	attributes []*AttributeInfo
+}
+
+func (v attributeRelationshipValidator) Description(ctx context.Context) string {
+	return v.MarkdownDescription(ctx)
+}
+
+func (v attributeRelationshipValidator) MarkdownDescription(_ context.Context) string {
+	return "Ensure that attributes requiring other attributes are configured together and conflicting attributes are not configured at the same time"
+}
+
+func (v attributeRelationshipValidator) ValidateResource(_ context.Context, request resource.ValidateConfigRequest, response *resource.ValidateConfigResponse) {
+	response.Diagnostics.Append(validateAttributeRelationships(v.attributes, request.Config.Raw, path.Empty())...)
+}
+
+func (v attributeRelationshipValidator) ValidateDataSource(_ context.Context, request datasource.ValidateConfigRequest, response *datasource.ValidateConfigResponse) {
+	response.Diagnostics.Append(validateAttributeRelationships(v.attributes, request.Config.Raw, path.Empty())...)
+}
+
+// Checks the configured values on one level of the schema and recurses into the configured Struct attributes.
+// Unknown values are skipped as they cannot be judged until they are known.
+func validateAttributeRelationships(attributes []*AttributeInfo, config tftypes.Value, parent path.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	if !config.IsKnown() || config.IsNull() {
+		return diags
+	}
+	values := map[string]tftypes.Value{}
+	if err := config.As(&values); err != nil {
+		diags.AddAttributeError(parent, "Unable to validate attribute relationships", err.Error())
+		return diags
+	}
+	isConfigured := func(name string) bool {
+		v, ok := values[name]
+		return ok && v.IsKnown() && !v.IsNull()
+	}
+	for _, attr := range attributes {
+		if !isConfigured(attr.TerraformName) {
+			continue
+		}
+		attrPath := parent.AtName(attr.TerraformName)
+		if len(attr.Attributes) != 0 {
+			diags.Append(validateAttributeRelationships(attr.Attributes, values[attr.TerraformName], attrPath)...)
+		}
+		for _, required := range attr.Requires {
+			v, ok := values[required]
+			if ok && !v.IsKnown() {
+				continue
+			}
+			// The write-only alternative of a resource attribute can be configured instead
+			if v, ok := values[required+writeOnlySuffix]; ok && (!v.IsKnown() || !v.IsNull()) {
+				continue
+			}
+			if !isConfigured(required) {
+				diags.Append(validatordiag.InvalidAttributeCombinationDiagnostic(
+					attrPath,
+					fmt.Sprintf("attribute %q must be specified when %q is specified", parent.AtName(required), attrPath),
+				))
+			}
+		}
+		for _, conflicting := range attr.ConflictsWith {
+			// The write-only alternative of a resource attribute conflicts in the same way
+			for _, name := range []string{conflicting, conflicting + writeOnlySuffix} {
+				if name != attr.TerraformName && isConfigured(name) {
+					diags.Append(validatordiag.InvalidAttributeCombinationDiagnostic(
+						attrPath,
+						fmt.Sprintf("attribute %q cannot be specified when %q is specified", parent.AtName(name), attrPath),
+					))
+				}
+			}
+		}
+	}
+	return diags
+}
+
+// relationshipValidatorTypes are the concrete types of the AlsoRequires and ConflictsWith validators used in the
+// generated attributes. They are shared by the validators of all attribute types but not exported, so they are taken
+// from the validators the constructors return.
+var relationshipValidatorTypes = map[reflect.Type]bool{
+	reflect.TypeOf(stringvalidator.AlsoRequires()):  true,
+	reflect.TypeOf(stringvalidator.ConflictsWith()): true,
+	reflect.TypeOf(int64validator.AlsoRequires()):   true,
+	reflect.TypeOf(int64validator.ConflictsWith()):  true,
+	reflect.TypeOf(boolvalidator.AlsoRequires()):    true,
+	reflect.TypeOf(boolvalidator.ConflictsWith()):   true,
+}
+
+// withoutRelationshipValidators removes the AlsoRequires and ConflictsWith attribute validators, and any nil
+// validators, from the generated validators. The same constraints are enforced from the Requires and ConflictsWith
+// metadata by attributeRelationshipValidator, and keeping both would report every violation twice.
+func withoutRelationshipValidators[T any](validators []T) []T {
+	var result []T
+	for _, v := range validators {
+		validatorType := reflect.TypeOf(v)
+		if validatorType == nil {
+			continue
+		}
+		if validatorType.Kind() == reflect.Pointer {
+			validatorType = validatorType.Elem()
+		}
+		if relationshipValidatorTypes[validatorType] {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package broker
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestValidateAttributeRelationships(t *testing.T) {
+	thresholdAttributes := []*AttributeInfo{
+		{TerraformName: "clear_percent", Requires: []string{"set_percent"}, ConflictsWith: []string{"clear_value", "set_value"}},
+		{TerraformName: "clear_value", Requires: []string{"set_value"}, ConflictsWith: []string{"clear_percent", "set_percent"}},
+		{TerraformName: "set_percent", Requires: []string{"clear_percent"}, ConflictsWith: []string{"clear_value", "set_value"}},
+		{TerraformName: "set_value", Requires: []string{"clear_value"}, ConflictsWith: []string{"clear_percent", "set_percent"}},
+	}
+	attributes := []*AttributeInfo{
+		{TerraformName: "username", Requires: []string{"password"}},
+		{TerraformName: "password", ConflictsWith: []string{"token"}},
+		{TerraformName: "password_wo", WriteOnly: true, ConflictsWith: []string{"password", "token"}},
+		{TerraformName: "token", ConflictsWith: []string{"password"}},
+		{TerraformName: "threshold", Attributes: thresholdAttributes},
+	}
+	thresholdType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"clear_percent": tftypes.Number,
+		"clear_value":   tftypes.Number,
+		"set_percent":   tftypes.Number,
+		"set_value":     tftypes.Number,
+	}}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"username":    tftypes.String,
+		"password":    tftypes.String,
+		"password_wo": tftypes.String,
+		"token":       tftypes.String,
+		"threshold":   thresholdType,
+	}}
+	threshold := func(clearPercent, clearValue, setPercent, setValue any) tftypes.Value {
+		return tftypes.NewValue(thresholdType, map[string]tftypes.Value{
+			"clear_percent": tftypes.NewValue(tftypes.Number, clearPercent),
+			"clear_value":   tftypes.NewValue(tftypes.Number, clearValue),
+			"set_percent":   tftypes.NewValue(tftypes.Number, setPercent),
+			"set_value":     tftypes.NewValue(tftypes.Number, setValue),
+		})
+	}
+	config := func(username, password any, threshold tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"username":    tftypes.NewValue(tftypes.String, username),
+			"password":    tftypes.NewValue(tftypes.String, password),
+			"password_wo": tftypes.NewValue(tftypes.String, nil),
+			"token":       tftypes.NewValue(tftypes.String, nil),
+			"threshold":   threshold,
+		})
+	}
+	with := func(config tftypes.Value, name string, value any) tftypes.Value {
+		values := map[string]tftypes.Value{}
+		_ = config.As(&values)
+		values[name] = tftypes.NewValue(tftypes.String, value)
+		return tftypes.NewValue(objectType, values)
+	}
+	tests := []struct {
+		name      string
+		config    tftypes.Value
+		wantPaths []path.Path
+	}{
+		{"Valid", config("user", "pass", threshold(60, nil, 80, nil)), nil},
+		{"NullStruct", config(nil, nil, tftypes.NewValue(thresholdType, nil)), nil},
+		{"MissingRequired", config("user", nil, tftypes.NewValue(thresholdType, nil)), []path.Path{path.Root("username")}},
+		{"UnknownRequired", config("user", tftypes.UnknownValue, tftypes.NewValue(thresholdType, nil)), nil},
+		{"WriteOnlyRequired", with(config("user", nil, tftypes.NewValue(thresholdType, nil)), "password_wo", "pass"), nil},
+		{"WriteOnlyConflict", with(config("user", "pass", tftypes.NewValue(thresholdType, nil)), "password_wo", "pass"), []path.Path{path.Root("password_wo")}},
+		{"Conflict", with(config(nil, "pass", tftypes.NewValue(thresholdType, nil)), "token", "token"), []path.Path{path.Root("password"), path.Root("token")}},
+		{"ConflictWithWriteOnly", with(with(config(nil, nil, tftypes.NewValue(thresholdType, nil)), "password_wo", "pass"), "token", "token"), []path.Path{path.Root("password_wo"), path.Root("token")}},
+		{"NestedMissingRequired", config(nil, nil, threshold(nil, nil, 80, nil)), []path.Path{path.Root("threshold").AtName("set_percent")}},
+		{"NestedConflict", config(nil, nil, threshold(60, 10, 80, 20)), []path.Path{
+			path.Root("threshold").AtName("clear_percent"),
+			path.Root("threshold").AtName("clear_percent"),
+			path.Root("threshold").AtName("clear_value"),
+			path.Root("threshold").AtName("clear_value"),
+			path.Root("threshold").AtName("set_percent"),
+			path.Root("threshold").AtName("set_percent"),
+			path.Root("threshold").AtName("set_value"),
+			path.Root("threshold").AtName("set_value"),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diags := validateAttributeRelationships(attributes, tt.config, path.Empty())
+			if len(diags) != len(tt.wantPaths) {
+				t.Fatalf("validateAttributeRelationships() returned %d diagnostics, want %d: %v", len(diags), len(tt.wantPaths), diags)
+			}
+			for i, d := range diags {
+				withPath, ok := d.(interface{ Path() path.Path })
+				if !ok || !withPath.Path().Equal(tt.wantPaths[i]) {
+					t.Errorf("diagnostic %d = %v, want attribute path %v", i, d, tt.wantPaths[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithoutRelationshipValidators(t *testing.T) {
+	validators := []validator.Int64{
+		int64validator.AlsoRequires(path.MatchRelative().AtParent().AtName("set_percent")),
+		int64validator.Between(0, 100),
+		int64validator.ConflictsWith(path.MatchRelative().AtParent().AtName("set_value")),
+		nil,
+	}
+	result := withoutRelationshipValidators(validators)
+	if len(result) != 1 {
+		t.Fatalf("withoutRelationshipValidators() kept %d validators, want 1", len(result))
+	}
+	stringValidators := []validator.String{
+		stringvalidator.AlsoRequires(path.MatchRelative().AtParent().AtName("password")),
+		stringvalidator.LengthBetween(0, 32),
+	}
+	if result := withoutRelationshipValidators(stringValidators); len(result) != 1 {
+		t.Errorf("withoutRelationshipValidators() kept %d string validators, want 1", len(result))
+	}
+}
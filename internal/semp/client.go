@@ -20,13 +20,14 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -34,15 +35,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
-var (
-	ErrResourceNotFound        = errors.New("resource not found")
-	ErrBadRequest              = errors.New("bad request")
-	ErrInvalidPath             = errors.New("invalid path")
-	ErrProviderParametersError = errors.New("provider parameters error")
-)
-
-var firstRequest = true
-
 type Client struct {
 	*retryablehttp.Client
 	url                string
@@ -55,6 +47,11 @@ type Client struct {
 	requestMinInterval time.Duration
 	requestTimeout     time.Duration
 	rateLimiter        <-chan time.Time
+	firstRequestDone   atomic.Bool
+	clientCertificate  *tls.Certificate
+	rootCAs            *x509.CertPool
+	tlsServerName      string
+	oauth              *oauthTokenSource
 }
 
 const (
@@ -65,8 +62,6 @@ const (
 	DefaultRetries          = 10
 )
 
-var Cookies = map[string]*http.Cookie{}
-
 type Option func(*Client)
 
 func BasicAuth(username, password string) Option {
@@ -82,6 +77,40 @@ func BearerToken(bearerToken string) Option {
 	}
 }
 
+// OAuthClientCredentials makes the client obtain and refresh bearer tokens from tokenURL using the OAuth2 client
+// credentials grant, instead of using a static bearer token.
+func OAuthClientCredentials(tokenURL, clientID, clientSecret string, scopes []string) Option {
+	return func(client *Client) {
+		if tokenURL == "" {
+			return
+		}
+		client.oauth = &oauthTokenSource{
+			tokenURL:     tokenURL,
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			scopes:       scopes,
+		}
+	}
+}
+
+func ClientCertificate(certificate *tls.Certificate) Option {
+	return func(client *Client) {
+		client.clientCertificate = certificate
+	}
+}
+
+func RootCAs(rootCAs *x509.CertPool) Option {
+	return func(client *Client) {
+		client.rootCAs = rootCAs
+	}
+}
+
+func TLSServerName(serverName string) Option {
+	return func(client *Client) {
+		client.tlsServerName = serverName
+	}
+}
+
 func Retries(numRetries int64, retryMinInterval, retryMaxInterval time.Duration) Option {
 	return func(client *Client) {
 		client.retries = numRetries
@@ -98,13 +127,7 @@ func RequestLimits(requestTimeoutDuration, requestMinInterval time.Duration) Opt
 }
 
 func NewClient(url string, insecure_skip_verify bool, providerClient bool, options ...Option) *Client {
-	tr := &http.Transport{
-		TLSClientConfig:     &tls.Config{InsecureSkipVerify: insecure_skip_verify},
-		MaxIdleConnsPerHost: 10,
-		Proxy:               http.ProxyFromEnvironment,
-	}
 	retryClient := retryablehttp.NewClient()
-	retryClient.HTTPClient.Transport = tr
 	if !providerClient {
 		retryClient.Logger = nil
 	}
@@ -118,6 +141,26 @@ func NewClient(url string, insecure_skip_verify bool, providerClient bool, optio
 	for _, o := range options {
 		o(client)
 	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: insecure_skip_verify,
+		RootCAs:            client.rootCAs,
+		ServerName:         client.tlsServerName,
+	}
+	if client.clientCertificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*client.clientCertificate}
+	}
+	client.HTTPClient.Transport = &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		MaxIdleConnsPerHost: 10,
+		Proxy:               http.ProxyFromEnvironment,
+	}
+	if client.oauth != nil {
+		// the token endpoint is reached through the same transport, including proxy and TLS settings
+		client.oauth.httpClient = &http.Client{
+			Transport: client.HTTPClient.Transport,
+			Timeout:   client.requestTimeout,
+		}
+	}
 	client.Client.RetryMax = int(client.retries)
 	client.Client.RetryWaitMin = client.retryMinInterval
 	client.Client.RetryWaitMax = client.retryMaxInterval
@@ -132,7 +175,6 @@ func NewClient(url string, insecure_skip_verify bool, providerClient bool, optio
 		close(ch)
 		client.rateLimiter = ch
 	}
-	firstRequest = true
 	return client
 }
 
@@ -154,33 +196,28 @@ func (c *Client) RequestWithBody(ctx context.Context, method, url string, body a
 }
 
 func (c *Client) doRequest(request *http.Request) ([]byte, error) {
-	if !firstRequest {
-		// the value doesn't matter, it is waiting for the value that matters
-		<-c.rateLimiter
-	} else {
-		// only skip rate limiter for the first request
-		firstRequest = false
-	}
 	if request.Method != http.MethodGet {
 		request.Header.Set("Content-Type", "application/json")
 	}
-	if c.bearerToken != "" {
-		request.Header.Set("Authorization", "Bearer "+c.bearerToken)
-	} else if c.username != "" {
-		request.SetBasicAuth(c.username, c.password)
-	} else {
-		return nil, fmt.Errorf("either username or bearer token must be provided to access the broker")
-	}
-	var response *http.Response
-	var err error
-	response, err = c.StandardClient().Do(request)
+	response, oauthToken, err := c.send(request)
 	if err != nil || response == nil {
 		return nil, err
 	}
+	if response.StatusCode == http.StatusUnauthorized && c.oauth != nil {
+		// the token may have been revoked or expired early, refresh it and retry once
+		response.Body.Close()
+		response, err = c.retryWithRefreshedToken(request, oauthToken)
+		if err != nil || response == nil {
+			return nil, err
+		}
+	}
 	defer response.Body.Close()
 	rawBody, err := io.ReadAll(response.Body)
-	if err != nil || (response.StatusCode != http.StatusOK && response.StatusCode != http.StatusBadRequest) {
-		return nil, fmt.Errorf("could not perform request: status %v (%v) during %v to %v, response body:\n%s", response.StatusCode, response.Status, request.Method, request.URL, rawBody)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response body: status %v (%v) during %v to %v: %w", response.StatusCode, response.Status, request.Method, request.URL, err)
+	}
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusBadRequest {
+		return nil, newErrorFromResponse(request, response.StatusCode, rawBody)
 	}
 	if _, err := io.Copy(io.Discard, response.Body); err != nil {
 		return nil, fmt.Errorf("response processing error: during %v to %v", request.Method, request.URL)
@@ -188,6 +225,48 @@ func (c *Client) doRequest(request *http.Request) ([]byte, error) {
 	return rawBody, nil
 }
 
+// send sends a request with the authentication of the client, waiting for the rate limiter. Returns the OAuth token
+// the request was sent with, if any.
+func (c *Client) send(request *http.Request) (*http.Response, string, error) {
+	// only skip rate limiter for the first request of this client
+	if c.firstRequestDone.Swap(true) {
+		// the value doesn't matter, it is waiting for the value that matters
+		<-c.rateLimiter
+	}
+	var oauthToken string
+	if c.oauth != nil {
+		var err error
+		oauthToken, err = c.oauth.Token(request.Context())
+		if err != nil {
+			return nil, "", err
+		}
+		request.Header.Set("Authorization", "Bearer "+oauthToken)
+	} else if c.bearerToken != "" {
+		request.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	} else if c.username != "" {
+		request.SetBasicAuth(c.username, c.password)
+	} else if c.clientCertificate == nil {
+		return nil, "", fmt.Errorf("either username, bearer token, OAuth client credentials or client certificate must be provided to access the broker")
+	}
+	response, err := c.StandardClient().Do(request)
+	return response, oauthToken, err
+}
+
+// retryWithRefreshedToken sends a request again with a new OAuth token, through the rate limiter like any request
+func (c *Client) retryWithRefreshedToken(request *http.Request, rejectedToken string) (*http.Response, error) {
+	c.oauth.Invalidate(rejectedToken)
+	retryRequest := request.Clone(request.Context())
+	if request.GetBody != nil {
+		var err error
+		retryRequest.Body, err = request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+	}
+	response, _, err := c.send(retryRequest)
+	return response, err
+}
+
 func parseResponseAsObject(ctx context.Context, request *http.Request, dataResponse []byte) (map[string]any, error) {
 	data := map[string]any{}
 	err := json.Unmarshal(dataResponse, &data)
@@ -204,18 +283,15 @@ func parseResponseAsObject(ctx context.Context, request *http.Request, dataRespo
 		rawData, ok = data["meta"]
 		if ok {
 			data, _ = rawData.(map[string]any)
-			if data["responseCode"].(float64) == http.StatusOK {
+			if responseCode, _ := data["responseCode"].(float64); responseCode == http.StatusOK {
 				// this is valid response for delete
 				return nil, nil
 			}
-			description := data["error"].(map[string]interface{})["description"].(string)
-			status := data["error"].(map[string]interface{})["status"].(string)
-			if status == "NOT_FOUND" {
-				// resource not found is a special type we want to return
-				return nil, fmt.Errorf("request failed from %v to %v, %v, %v, %w", request.Method, request.URL, description, status, ErrResourceNotFound)
+			sempError := newErrorFromMeta(request, data)
+			if sempError.Status != StatusNotFound {
+				tflog.Error(ctx, fmt.Sprintf("SEMP request returned %v, %v", sempError.Description, sempError.Status))
 			}
-			tflog.Error(ctx, fmt.Sprintf("SEMP request returned %v, %v", description, status))
-			return nil, fmt.Errorf("request failed for %v using %v, %v, %v", request.URL, request.Method, description, status)
+			return nil, sempError
 		}
 	}
 	return nil, fmt.Errorf("could not parse response details from %v to %v, response body was:\n%s", request.Method, request.URL, dataResponse)
@@ -255,17 +331,8 @@ func parseResponseForGenerator(c *Client, ctx context.Context, basePath string,
 		rawData, ok = data["meta"]
 		if ok {
 			data, _ = rawData.(map[string]any)
-			description := data["error"].(map[string]interface{})["description"].(string)
-			status := data["error"].(map[string]interface{})["status"].(string)
-			if status == "NOT_FOUND" {
-				// resource not found is a special type we want to return
-				return nil, fmt.Errorf("%v, %v, %w", description, status, ErrResourceNotFound)
-			}
-			if status == "INVALID_PATH" {
-				// resource not found is a special type we want to return
-				return nil, fmt.Errorf("%v, %v, %w", description, status, ErrInvalidPath)
-			}
-			return nil, fmt.Errorf("%v, %v, %w", description, status, ErrBadRequest)
+			// errors.Is matches ErrResourceNotFound, ErrInvalidPath or ErrBadRequest depending on the status
+			return nil, newErrorFromMeta(request, data)
 		}
 	}
 	return nil, nil
@@ -0,0 +1,108 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrResourceNotFound        = errors.New("resource not found")
+	ErrBadRequest              = errors.New("bad request")
+	ErrInvalidPath             = errors.New("invalid path")
+	ErrProviderParametersError = errors.New("provider parameters error")
+)
+
+// SEMP error statuses that are handled specifically
+const (
+	StatusNotFound    = "NOT_FOUND"
+	StatusInvalidPath = "INVALID_PATH"
+)
+
+// Error is a failed SEMP request, carrying the details reported by the broker.
+// Use errors.As to access the details; errors.Is matches ErrResourceNotFound or ErrInvalidPath depending on the SEMP
+// status, and ErrBadRequest for any other request rejected by the broker.
+type Error struct {
+	HTTPStatus  int    // The HTTP status code of the response
+	Status      string // The SEMP error status, for example NOT_FOUND or ALREADY_EXISTS. Empty if not reported.
+	Code        int    // The SEMP error code. Zero if not reported.
+	Description string // The SEMP error description, or the raw response body if it could not be parsed
+	Method      string
+	Path        string
+}
+
+func (e *Error) Error() string {
+	if e.Status == "" {
+		return fmt.Sprintf("request failed for %v using %v, status %v (%v), response body:\n%s", e.Path, e.Method, e.HTTPStatus, http.StatusText(e.HTTPStatus), e.Description)
+	}
+	return fmt.Sprintf("request failed for %v using %v, %v, %v", e.Path, e.Method, e.Description, e.Status)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrResourceNotFound:
+		return e.Status == StatusNotFound
+	case ErrInvalidPath:
+		return e.Status == StatusInvalidPath
+	case ErrBadRequest:
+		return e.HTTPStatus == http.StatusBadRequest && e.Status != StatusNotFound && e.Status != StatusInvalidPath
+	}
+	return false
+}
+
+// newErrorFromMeta creates an Error from the "meta" part of a SEMP response
+func newErrorFromMeta(request *http.Request, meta map[string]any) *Error {
+	sempError := &Error{
+		Method: request.Method,
+		Path:   request.URL.String(),
+	}
+	if responseCode, ok := meta["responseCode"].(float64); ok {
+		sempError.HTTPStatus = int(responseCode)
+	}
+	if details, ok := meta["error"].(map[string]any); ok {
+		sempError.Status, _ = details["status"].(string)
+		sempError.Description, _ = details["description"].(string)
+		if code, ok := details["code"].(float64); ok {
+			sempError.Code = int(code)
+		}
+	}
+	return sempError
+}
+
+// newErrorFromResponse creates an Error from an unsuccessful HTTP response, using the SEMP error details from the
+// body if available
+func newErrorFromResponse(request *http.Request, statusCode int, body []byte) *Error {
+	data := map[string]any{}
+	if err := json.Unmarshal(body, &data); err == nil {
+		if meta, ok := data["meta"].(map[string]any); ok {
+			if _, ok := meta["error"]; ok {
+				sempError := newErrorFromMeta(request, meta)
+				sempError.HTTPStatus = statusCode
+				return sempError
+			}
+		}
+	}
+	return &Error{
+		HTTPStatus:  statusCode,
+		Description: string(body),
+		Method:      request.Method,
+		Path:        request.URL.String(),
+	}
+}
@@ -0,0 +1,96 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrorFromResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		want       Error
+		wantIs     error
+	}{
+		{
+			"NotFound",
+			http.StatusBadRequest,
+			`{"meta":{"error":{"code":6,"description":"Could not find match for msgVpnName test","status":"NOT_FOUND"},"responseCode":400}}`,
+			Error{HTTPStatus: http.StatusBadRequest, Status: "NOT_FOUND", Code: 6, Description: "Could not find match for msgVpnName test"},
+			ErrResourceNotFound,
+		},
+		{
+			"AlreadyExists",
+			http.StatusBadRequest,
+			`{"meta":{"error":{"code":10,"description":"Object already exists","status":"ALREADY_EXISTS"},"responseCode":400}}`,
+			Error{HTTPStatus: http.StatusBadRequest, Status: "ALREADY_EXISTS", Code: 10, Description: "Object already exists"},
+			ErrBadRequest,
+		},
+		{
+			"InvalidPath",
+			http.StatusBadRequest,
+			`{"meta":{"error":{"code":21,"description":"Invalid path","status":"INVALID_PATH"},"responseCode":400}}`,
+			Error{HTTPStatus: http.StatusBadRequest, Status: "INVALID_PATH", Code: 21, Description: "Invalid path"},
+			ErrInvalidPath,
+		},
+		{
+			"UnauthorizedWithEnvelope",
+			http.StatusUnauthorized,
+			`{"meta":{"error":{"code":8,"description":"Unauthorized","status":"UNAUTHORIZED"},"responseCode":401}}`,
+			Error{HTTPStatus: http.StatusUnauthorized, Status: "UNAUTHORIZED", Code: 8, Description: "Unauthorized"},
+			nil,
+		},
+		{
+			"NotFoundWithoutEnvelope",
+			http.StatusNotFound,
+			`page not found`,
+			Error{HTTPStatus: http.StatusNotFound, Description: "page not found"},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			client := NewClient(server.URL, false, false, BasicAuth("admin", "admin"), Retries(0, 0, 0), RequestLimits(DefaultRequestTimeout, 0))
+			_, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test")
+			var sempError *Error
+			if !errors.As(err, &sempError) {
+				t.Fatalf("RequestWithoutBody() error = %v, want *Error", err)
+			}
+			tt.want.Method = http.MethodGet
+			tt.want.Path = server.URL + "/msgVpns/test"
+			if *sempError != tt.want {
+				t.Errorf("RequestWithoutBody() error = %+v, want %+v", *sempError, tt.want)
+			}
+			for _, sentinel := range []error{ErrResourceNotFound, ErrInvalidPath, ErrBadRequest} {
+				if errors.Is(err, sentinel) != (sentinel == tt.wantIs) {
+					t.Errorf("errors.Is(%v, %v) = %v", err, sentinel, errors.Is(err, sentinel))
+				}
+			}
+		})
+	}
+}
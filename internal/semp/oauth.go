@@ -0,0 +1,138 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tokens are refreshed this long before they expire so that they don't expire in flight
+const tokenExpiryMargin = 30 * time.Second
+
+// oauthTokenSource obtains access tokens from an OAuth2 token endpoint using the client credentials grant and caches
+// them until shortly before they expire.
+type oauthTokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	httpClient   *http.Client
+	lock         sync.Mutex
+	token        string
+	expiry       time.Time
+}
+
+// Token returns the cached access token, requesting a new one from the token endpoint if there is none or it is
+// about to expire.
+func (s *oauthTokenSource) Token(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Before(s.expiry)) {
+		return s.token, nil
+	}
+	token, expiresIn, err := s.requestToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.token = token
+	s.expiry = time.Time{}
+	if expiresIn > 0 {
+		margin := tokenExpiryMargin
+		if expiresIn < 2*margin {
+			margin = expiresIn / 2
+		}
+		s.expiry = time.Now().Add(expiresIn - margin)
+	}
+	return s.token, nil
+}
+
+// Invalidate drops the cached token if it is still the one that has been rejected, so that the next call to Token
+// requests a new one.
+func (s *oauthTokenSource) Invalidate(token string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.token == token {
+		s.token = ""
+	}
+}
+
+func (s *oauthTokenSource) requestToken(ctx context.Context) (string, time.Duration, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+	response, err := s.httpClient.Do(request)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not request OAuth token from %v: %w", s.tokenURL, err)
+	}
+	defer response.Body.Close()
+	rawBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not read OAuth token response from %v: %w", s.tokenURL, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("could not obtain OAuth token: status %v (%v) from %v, response body:\n%s", response.StatusCode, response.Status, s.tokenURL, rawBody)
+	}
+	tokenResponse := struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}{}
+	if err := json.Unmarshal(rawBody, &tokenResponse); err != nil {
+		return "", 0, fmt.Errorf("could not parse OAuth token response from %v: %w", s.tokenURL, err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", 0, fmt.Errorf("OAuth token response from %v does not contain an access token", s.tokenURL)
+	}
+	if tokenResponse.TokenType != "" && !strings.EqualFold(tokenResponse.TokenType, "bearer") {
+		return "", 0, fmt.Errorf("unsupported OAuth token type %q received from %v", tokenResponse.TokenType, s.tokenURL)
+	}
+	return tokenResponse.AccessToken, time.Duration(tokenResponse.ExpiresIn) * time.Second, nil
+}
+
+// RequestOAuthToken obtains a new access token from tokenURL using the client credentials grant and returns it with
+// its lifetime, which is zero if the token endpoint doesn't specify one. The token endpoint is reached through the
+// same transport as the broker, including proxy and TLS settings. The token is not cached by the client.
+func (c *Client) RequestOAuthToken(ctx context.Context, tokenURL, clientID, clientSecret string, scopes []string) (string, time.Duration, error) {
+	source := &oauthTokenSource{
+		tokenURL:     tokenURL,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		scopes:       scopes,
+		httpClient: &http.Client{
+			Transport: c.HTTPClient.Transport,
+			Timeout:   c.requestTimeout,
+		},
+	}
+	return source.requestToken(ctx)
+}
@@ -0,0 +1,164 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testTokenEndpoint struct {
+	lock      sync.Mutex
+	issued    int
+	expiresIn int
+	fail      bool
+}
+
+func (e *testTokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	clientID, clientSecret, ok := r.BasicAuth()
+	if e.fail || !ok || clientID != "client" || clientSecret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+		return
+	}
+	e.issued++
+	_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, e.issued, e.expiresIn)
+}
+
+func (e *testTokenEndpoint) currentToken() string {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return fmt.Sprintf("token-%d", e.issued)
+}
+
+func newTestOAuthClient(t *testing.T, tokenEndpoint *testTokenEndpoint, requestMinInterval time.Duration) *Client {
+	tokenServer := httptest.NewServer(tokenEndpoint)
+	t.Cleanup(tokenServer.Close)
+	// The SEMP server only accepts the most recently issued token
+	sempServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+tokenEndpoint.currentToken() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"msgVpnName":"test"}}`))
+	}))
+	t.Cleanup(sempServer.Close)
+	return NewClient(sempServer.URL, false, false,
+		OAuthClientCredentials(tokenServer.URL, "client", "secret", []string{"semp"}),
+		Retries(0, 0, 0),
+		RequestLimits(DefaultRequestTimeout, requestMinInterval))
+}
+
+func TestOAuthTokenIsCached(t *testing.T) {
+	tokenEndpoint := &testTokenEndpoint{expiresIn: 3600}
+	client := newTestOAuthClient(t, tokenEndpoint, 0)
+	for i := 0; i < 3; i++ {
+		if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+	if tokenEndpoint.issued != 1 {
+		t.Errorf("expected 1 token to be issued, got %d", tokenEndpoint.issued)
+	}
+}
+
+func TestOAuthTokenIsRefreshedOnExpiry(t *testing.T) {
+	tokenEndpoint := &testTokenEndpoint{expiresIn: 3600}
+	client := newTestOAuthClient(t, tokenEndpoint, 0)
+	if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	client.oauth.expiry = time.Now().Add(-time.Second)
+	if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err != nil {
+		t.Fatalf("request after expiry failed: %v", err)
+	}
+	if tokenEndpoint.issued != 2 {
+		t.Errorf("expected 2 tokens to be issued, got %d", tokenEndpoint.issued)
+	}
+}
+
+func TestOAuthTokenIsRefreshedOnUnauthorized(t *testing.T) {
+	tokenEndpoint := &testTokenEndpoint{expiresIn: 3600}
+	client := newTestOAuthClient(t, tokenEndpoint, 0)
+	if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	// Revoke the cached token by issuing another one behind the client's back
+	tokenEndpoint.lock.Lock()
+	tokenEndpoint.issued++
+	tokenEndpoint.lock.Unlock()
+	if _, err := client.RequestWithBody(context.Background(), http.MethodPut, "/msgVpns/test", map[string]any{"msgVpnName": "test"}); err != nil {
+		t.Fatalf("request with refreshed token failed: %v", err)
+	}
+	if tokenEndpoint.issued != 3 {
+		t.Errorf("expected 3 tokens to be issued, got %d", tokenEndpoint.issued)
+	}
+}
+
+func TestOAuthRetryIsRateLimited(t *testing.T) {
+	tokenEndpoint := &testTokenEndpoint{expiresIn: 3600}
+	interval := 50 * time.Millisecond
+	start := time.Now()
+	client := newTestOAuthClient(t, tokenEndpoint, interval)
+	if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	tokenEndpoint.lock.Lock()
+	tokenEndpoint.issued++
+	tokenEndpoint.lock.Unlock()
+	if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err != nil {
+		t.Fatalf("request with refreshed token failed: %v", err)
+	}
+	// the second request and its retry each wait for the rate limiter
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("requests took %v, want the retry to wait for the rate limiter", elapsed)
+	}
+}
+
+func TestOAuthTokenEndpointFailure(t *testing.T) {
+	tokenEndpoint := &testTokenEndpoint{fail: true}
+	client := newTestOAuthClient(t, tokenEndpoint, 0)
+	if _, err := client.RequestWithoutBody(context.Background(), http.MethodGet, "/msgVpns/test"); err == nil {
+		t.Fatal("expected request to fail when no token can be obtained")
+	}
+}
+
+func TestRequestOAuthToken(t *testing.T) {
+	tokenEndpoint := &testTokenEndpoint{expiresIn: 300}
+	tokenServer := httptest.NewServer(tokenEndpoint)
+	t.Cleanup(tokenServer.Close)
+	client := NewClient("", false, false, Retries(0, 0, 0), RequestLimits(DefaultRequestTimeout, 0))
+	for i := 1; i <= 2; i++ {
+		token, expiresIn, err := client.RequestOAuthToken(context.Background(), tokenServer.URL, "client", "secret", nil)
+		if err != nil {
+			t.Fatalf("token request %d failed: %v", i, err)
+		}
+		if want := fmt.Sprintf("token-%d", i); token != want || expiresIn != 300*time.Second {
+			t.Errorf("RequestOAuthToken() = %v, %v, want %v, 5m0s", token, expiresIn, want)
+		}
+	}
+	if _, _, err := client.RequestOAuthToken(context.Background(), tokenServer.URL, "client", "wrong", nil); err == nil {
+		t.Error("expected token request with wrong client secret to fail")
+	}
+}
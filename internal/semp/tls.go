@@ -0,0 +1,69 @@
+// terraform-provider-solacebroker
+//
+// Copyright 2024 Solace Corporation. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// ReadPEM returns PEM encoded content that is either provided directly or read from filePath. The names are the
+// parameter names used in error messages. Empty result means that neither was provided. Providing both is an error.
+func ReadPEM(contentName string, content string, fileName string, filePath string) ([]byte, error) {
+	if content != "" && filePath != "" {
+		return nil, fmt.Errorf("only one of %v or %v can be provided", contentName, fileName)
+	}
+	if filePath != "" {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read %v: %w", fileName, err)
+		}
+		return data, nil
+	}
+	return []byte(content), nil
+}
+
+// LoadCertPool creates a pool of trusted CA certificates from PEM encoded certificates.
+// Returns nil if no certificates have been provided, meaning that the host's root CA set will be used.
+func LoadCertPool(certificates []byte) (*x509.CertPool, error) {
+	if len(certificates) == 0 {
+		return nil, nil
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(certificates) {
+		return nil, fmt.Errorf("no valid PEM encoded CA certificates found")
+	}
+	return pool, nil
+}
+
+// LoadClientCertificate parses a PEM encoded certificate chain and its private key for mutual TLS authentication.
+// Returns nil if neither has been provided.
+func LoadClientCertificate(certificate []byte, privateKey []byte) (*tls.Certificate, error) {
+	if len(certificate) == 0 && len(privateKey) == 0 {
+		return nil, nil
+	}
+	if len(certificate) == 0 || len(privateKey) == 0 {
+		return nil, fmt.Errorf("both client certificate and client private key must be provided for client certificate authentication")
+	}
+	cert, err := tls.X509KeyPair(certificate, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate or private key: %w", err)
+	}
+	return &cert, nil
+}
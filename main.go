@@ -46,7 +46,7 @@ func main() {
 		os.Exit(1)
 	}
 	broker.ProviderVersion = version
-	if len(os.Args) > 1 && (os.Args[1] == "generate" || os.Args[1] == "help" || os.Args[1] == "--help" || os.Args[1] == "-h" || os.Args[1] == "version") {
+	if len(os.Args) > 1 && (os.Args[1] == "generate" || os.Args[1] == "snapshot" || os.Args[1] == "diff" || os.Args[1] == "help" || os.Args[1] == "--help" || os.Args[1] == "-h" || os.Args[1] == "version") {
 		err := cmd.Execute()
 		if err != nil && err.Error() != "" {
 			fmt.Println(err)